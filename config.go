@@ -1,7 +1,141 @@
 package gjson
 
-import "github.com/cloudwego/gjson/internal/fast"
+import (
+	"github.com/cloudwego/gjson/internal/caching"
+	"github.com/cloudwego/gjson/internal/fast"
+)
 
 var (
 	FastStringEnable = fast.FastStringEnable
 )
+
+// CachePolicy is the eviction policy of the cache of parsed paths.
+type CachePolicy = caching.Policy
+
+const (
+	// CacheCLOCK evicts a path which has not been read since the clock hand
+	// last passed it. Reads are cheap and can run concurrently.
+	CacheCLOCK CachePolicy = caching.CLOCK
+	// CacheLRU evicts the least recently read path. Every read updates the
+	// recency list, so reads are serialized.
+	CacheLRU CachePolicy = caching.LRU
+)
+
+// PathCacheStats is the statistics of the cache of parsed paths.
+type PathCacheStats = caching.Stats
+
+// SetPathCache replaces the cache of parsed paths, used by FastPath, with an
+// empty one which holds at most capacity paths and evicts them by the policy.
+// Paths are not cached if capacity <= 0. The default is a CLOCK cache of
+// 10000 paths.
+func SetPathCache(capacity int, policy CachePolicy) {
+	fast.SetPathCache(capacity, policy)
+}
+
+// GetPathCacheStats returns the hits, misses and evictions of the cache of
+// parsed paths since the last call of SetPathCache, and its size.
+func GetPathCacheStats() PathCacheStats {
+	return fast.PathCacheStats()
+}
+
+// defaultMatchLimit limits the complexity of the match operation,
+// see the github.com/tidwall/match.MatchLimit function.
+const defaultMatchLimit = 10000
+
+// Options configures how a Parser searches json. The zero value is the legacy
+// behaviour of gjson, which is the default of the package-level functions
+// unless the GJSON_FAST_PATH and GJSON_FAST_STRING env vars are set.
+type Options struct {
+	// FastPath searches simple paths, and the literal prefix of other paths,
+	// using the native searcher. It is the GJSON_FAST_PATH env var.
+	FastPath bool
+	// FastString decodes strings using the native decoder.
+	// It is the GJSON_FAST_STRING=1 env var.
+	FastString bool
+	// ValidateUTF8 validates the UTF-8 of the strings being decoded, which
+	// are not found if invalid. It implies FastString, and it is the
+	// GJSON_FAST_STRING=2 env var. Parser.Valid is ValidStrict if it is set.
+	ValidateUTF8 bool
+	// DisableModifiers disables the modifier syntax, like DisableModifiers.
+	DisableModifiers bool
+	// DisableEscapeHTML disables escaping '<', '>' and '&' when encoding
+	// strings to json, like DisableEscapeHTML. The modifiers, such as
+	// @tostr, take no options, thus they still read DisableEscapeHTML.
+	DisableEscapeHTML bool
+	// MatchLimit limits the complexity of matching wildcards and patterns,
+	// zero means the default of 10000.
+	MatchLimit int
+	// ExactNumbers compares the numbers in queries exactly as decimals, such
+	// as #(amount>12345678901234567.89), rather than as float64s.
+	ExactNumbers bool
+	// ChronologicalTimes compares the RFC3339 strings in queries by the <, <=,
+	// > and >= ops chronologically, such as #(ts>"2024-01-01T00:00:00Z"),
+	// rather than as strings. The = and != ops still compare strings.
+	ChronologicalTimes bool
+}
+
+// DefaultOptions returns the options used by the package-level functions,
+// which are read from the env vars and the package variables.
+func DefaultOptions() Options {
+	return Options{
+		FastPath:          fast.FastPathEnable,
+		FastString:        fast.FastStringEnable,
+		ValidateUTF8:      fast.ValidStringEnable,
+		DisableModifiers:  DisableModifiers,
+		DisableEscapeHTML: DisableEscapeHTML,
+		MatchLimit:        defaultMatchLimit,
+	}
+}
+
+// The methods below read an option, where nil options mean the settings
+// of the package-level functions.
+
+func (o *Options) fastPath() bool {
+	if o == nil {
+		return fast.FastPathEnable
+	}
+	return o.FastPath
+}
+
+func (o *Options) fastString() bool {
+	if o == nil {
+		return fast.FastStringEnable
+	}
+	return o.FastString || o.ValidateUTF8
+}
+
+func (o *Options) validateUTF8() bool {
+	if o == nil {
+		return fast.ValidStringEnable
+	}
+	return o.ValidateUTF8
+}
+
+func (o *Options) modifiers() bool {
+	if o == nil {
+		return !DisableModifiers
+	}
+	return !o.DisableModifiers
+}
+
+func (o *Options) escapeHTML() bool {
+	if o == nil {
+		return !DisableEscapeHTML
+	}
+	return !o.DisableEscapeHTML
+}
+
+func (o *Options) matchLimit() int {
+	if o == nil || o.MatchLimit <= 0 {
+		return defaultMatchLimit
+	}
+	return o.MatchLimit
+}
+
+func (o *Options) exactNumbers() bool {
+	return o != nil && o.ExactNumbers
+}
+
+func (o *Options) chronologicalTimes() bool {
+	return o != nil && o.ChronologicalTimes
+}
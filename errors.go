@@ -0,0 +1,99 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrNotFound is the error of a path which is not found in a valid json.
+var ErrNotFound = errors.New("gjson: path not found")
+
+// GetE searches json for the specified path like Get, and tells why a value
+// is not found. The error is a *PathSyntaxError if Get fails to parse the
+// path, such as an unclosed query, as reported by ValidatePath. Otherwise
+// the json is validated, and the error is a *SyntaxError for a malformed
+// json, or ErrNotFound.
+//
+//	res, err := gjson.GetE(json, "name.last")
+//	if errors.Is(err, gjson.ErrNotFound) {
+//		// name.last is missing
+//	} else if err != nil {
+//		return err
+//	}
+//
+// A value found in a malformed json is returned without an error, like Get
+// does, since the json is not validated then.
+func GetE(json, path string) (Result, error) {
+	return getE(json, path, nil)
+}
+
+// GetBytesE searches json for the specified path, see GetE.
+// If working with bytes, this method preferred over GetE(string(data), path)
+func GetBytesE(json []byte, path string) (Result, error) {
+	return getBytesE(json, path, nil)
+}
+
+// GetE searches json for the compiled path like p.Get, and tells why a value
+// is not found, see GetE.
+func (p *Path) GetE(json string) (Result, error) {
+	return notFound(p.Get(json), json, p.path, p.opts)
+}
+
+func getE(json, path string, opts *Options) (Result, error) {
+	return notFound(getWith(json, path, opts), json, path, opts)
+}
+
+func getBytesE(json []byte, path string, opts *Options) (Result, error) {
+	res, err := getE(bytesString(json), path, opts)
+	return copyResult(res), err
+}
+
+// notFound returns the error for res which is not found in json by path.
+func notFound(res Result, json, path string, opts *Options) (Result, error) {
+	if res.Exists() {
+		return res, nil
+	}
+	if err := parseError(path, opts); err != nil {
+		return res, err
+	}
+	if err := validate(stringBytes(json), opts.validateUTF8()); err != nil {
+		return res, err
+	}
+	return res, ErrNotFound
+}
+
+// parseError returns the error of a path which Get fails to parse, which is
+// an unclosed query or multipath, or nil. Unlike ValidatePath, the paths
+// which are parsed but never match are not reported, while the error is the
+// one reported by ValidatePath.
+func parseError(path string, opts *Options) error {
+	if strings.IndexAny(path, "([{") < 0 {
+		// no queries or multipaths
+		return nil
+	}
+	cp := pathCompiler{src: path, opts: opts, nodes: make(map[string]*pathNode)}
+	_, err := cp.path(path)
+	if err == nil {
+		return nil
+	}
+	if verr := validatePath(path, opts); verr != nil {
+		return verr
+	}
+	return err
+}
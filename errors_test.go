@@ -0,0 +1,87 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetE(t *testing.T) {
+	json := `{"name":{"first":"Tom","last":"Anderson"},"friends":[{"last":"Murphy"},{"last":"Craig"}]}`
+	for _, path := range []string{"name.last", "friends.1.last", `friends.#(last=="Craig").last`, "[name.first,friends.#]"} {
+		res, err := GetE(json, path)
+		assert(t, err == nil && res.Raw == Get(json, path).Raw)
+		res, err = GetBytesE([]byte(json), path)
+		assert(t, err == nil && res.Raw == Get(json, path).Raw)
+	}
+
+	for _, path := range []string{"name.middle", "friends.2", `friends.#(last=="Smith")`, "name.first.0"} {
+		res, err := GetE(json, path)
+		assert(t, err == ErrNotFound && !res.Exists())
+	}
+
+	var perr *PathSyntaxError
+	_, err := GetE(json, `friends.#(last=="Murphy"`)
+	assert(t, errors.As(err, &perr) && perr.Offset == 8 && perr.Msg == "unclosed query")
+	_, err = GetE(`garbage`, `[name,age`)
+	assert(t, errors.As(err, &perr) && perr.Offset == 0)
+	_, err = GetE(json, `friends.#(a in [1,2)`)
+	assert(t, errors.As(err, &perr) && perr.Offset == 19 && perr.Msg == "unexpected ')' in query")
+	// the paths which Get parses are not reported, even if they never match
+	_, err = GetE(json, `friends.#(age=>40)`)
+	assert(t, err == ErrNotFound)
+	// keys starting with @ are not modifiers
+	res, err := GetE(`{"@context":{"@vocab":"x"}}`, "@context.@vocab")
+	assert(t, err == nil && res.String() == "x")
+	_, err = GetE(`{"@context":{}}`, "@context.@vocab")
+	assert(t, err == ErrNotFound)
+
+	var serr *SyntaxError
+	_, err = GetE(`{"name":{"first":"Tom",`, "name.last")
+	assert(t, errors.As(err, &serr) && serr.Offset == 23)
+	_, err = GetBytesE(nil, "name")
+	assert(t, errors.As(err, &serr))
+	// found in a malformed json, which is not validated
+	res, err = GetE(`{"name":"Tom",`, "name")
+	assert(t, err == nil && res.String() == "Tom")
+
+	p := MustCompile("name.middle")
+	_, err = p.GetE(json)
+	assert(t, err == ErrNotFound)
+	res, err = p.GetE(`{"name":{"middle":"J"}}`)
+	assert(t, err == nil && res.String() == "J")
+
+	// compiled paths tell the same errors
+	for _, path := range []string{"@context.@vocab", "@context.x", `@context.#(a==1)`, `[name.x]`} {
+		res, err := GetE(`{"@context":{"@vocab":"x"}}`, path)
+		pres, perr := MustCompile(path).GetE(`{"@context":{"@vocab":"x"}}`)
+		assert(t, err == perr && res.Raw == pres.Raw)
+	}
+
+	strict := NewParser(Options{ValidateUTF8: true})
+	_, err = strict.GetE("{\"a\":\"\xff\"}", "b")
+	assert(t, errors.As(err, &serr))
+	_, err = NewParser(Options{}).GetBytesE([]byte("{\"a\":\"\xff\"}"), "b")
+	assert(t, err == ErrNotFound)
+
+	// the paths are not cached without the fast path
+	stats := GetPathCacheStats()
+	_, _ = NewParser(Options{}).GetE(json, `friends.#(last=="Craig").last`)
+	_, _ = NewParser(Options{}).GetE(json, "name.last")
+	assert(t, GetPathCacheStats() == stats)
+}
@@ -0,0 +1,366 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FieldError describes a struct field which cannot be extracted by Extract.
+type FieldError struct {
+	// Field is the name of the field
+	Field string
+	// Path is the path in the gjson tag of the field
+	Path string
+	// Err is a *PathSyntaxError of the path, ErrOverflow, or the error of
+	// Result.Unmarshal
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return "gjson: cannot extract field " + e.Field + " from path " + strconv.Quote(e.Path) + ": " +
+		strings.TrimPrefix(e.Err.Error(), "gjson: ")
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+var errExtractTarget = errors.New("gjson: Extract needs a non-nil pointer to a struct")
+
+// Extract fills the fields of the struct pointed by v, which have gjson tags,
+// with the values of their paths in json. A path may use the whole syntax,
+// such as queries, modifiers and multipaths.
+//
+//	var v struct {
+//		Names []string `gjson:"statuses.#.user.name"`
+//		Count int      `gjson:"statuses.#"`
+//		Top   string   `gjson:"statuses.#(retweets>100).text"`
+//	}
+//	err := gjson.Extract(json, &v)
+//
+// The values are converted by the accessors of Result, such as Int and
+// String, and a field of a Result, a time.Time or a json.Number is set by
+// Result, Time and Number. A slice is filled with the elements of an array,
+// a pointer is allocated, and a struct with gjson tags is extracted from the
+// value of its path, whose fields have paths relative to the value. Other
+// types, such as maps, are decoded by Result.Unmarshal.
+//
+// A field is not changed if its path is not found, and it is set to the zero
+// value by null. The untagged fields of embedded structs are extracted as
+// the fields of the struct. The paths are compiled once for a struct type.
+func Extract(json string, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errExtractTarget
+	}
+	return extractPlanOf(rv.Elem().Type()).extract(json, rv.Elem())
+}
+
+// extractPlan is the compiled fields of a struct type.
+type extractPlan struct {
+	fields []extractField
+	err    error
+}
+
+type extractField struct {
+	name  string
+	index []int
+	path  *Path
+	conv  extractConv
+}
+
+// extractConv sets v with a result, which is not null.
+type extractConv func(res Result, v reflect.Value) error
+
+func (p *extractPlan) extract(json string, v reflect.Value) error {
+	if p.err != nil {
+		return p.err
+	}
+	for i := range p.fields {
+		f := &p.fields[i]
+		res := f.path.Get(json)
+		if !res.Exists() {
+			continue
+		}
+		fv := fieldByIndex(v, f.index)
+		if res.Type == Null {
+			fv.Set(reflect.Zero(fv.Type()))
+			continue
+		}
+		if err := f.conv(res, fv); err != nil {
+			return &FieldError{Field: f.name, Path: f.path.String(), Err: err}
+		}
+	}
+	return nil
+}
+
+// fieldByIndex returns the field of v by its index, allocating the nil
+// pointers of embedded structs.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+var (
+	// extractPlans caches the *extractPlan of struct types
+	extractPlans sync.Map
+	// extractMu serializes building plans
+	extractMu sync.Mutex
+)
+
+func extractPlanOf(t reflect.Type) *extractPlan {
+	if p, ok := extractPlans.Load(t); ok {
+		return p.(*extractPlan)
+	}
+	extractMu.Lock()
+	defer extractMu.Unlock()
+	b := planBuilder{plans: make(map[reflect.Type]*extractPlan)}
+	p := b.plan(t)
+	// the plans are cached once complete, since they may refer to each other
+	for t, p := range b.plans {
+		extractPlans.Store(t, p)
+	}
+	return p
+}
+
+// planBuilder builds the plans of a struct type and of its nested structs.
+type planBuilder struct {
+	plans map[reflect.Type]*extractPlan
+}
+
+func (b *planBuilder) plan(t reflect.Type) *extractPlan {
+	if p := b.built(t); p != nil {
+		return p
+	}
+	p := &extractPlan{}
+	b.plans[t] = p
+	p.err = b.fields(p, t, nil)
+	return p
+}
+
+// built returns the plan of t, which has been built or is being built, or nil.
+func (b *planBuilder) built(t reflect.Type) *extractPlan {
+	if t == nil {
+		return nil
+	}
+	if p, ok := extractPlans.Load(t); ok {
+		return p.(*extractPlan)
+	}
+	return b.plans[t]
+}
+
+// fields adds the tagged fields of the struct type t, whose index is prefixed
+// by the index of the embedded struct.
+func (b *planBuilder) fields(p *extractPlan, t reflect.Type, prefix []int) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		index := append(append([]int(nil), prefix...), i)
+		tag := f.Tag.Get("gjson")
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			if st := embeddedStruct(f); st != nil {
+				if err := b.fields(p, st, index); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			// unexported
+			continue
+		}
+		path, err := Compile(tag)
+		if err != nil {
+			return &FieldError{Field: f.Name, Path: tag, Err: err}
+		}
+		conv := b.conv(f.Type)
+		// report the errors of the nested structs, even if they are not found
+		if np := b.built(elemStruct(f.Type)); np != nil && np.err != nil {
+			return &FieldError{Field: f.Name, Path: tag, Err: np.err}
+		}
+		p.fields = append(p.fields, extractField{name: f.Name, index: index, path: path, conv: conv})
+	}
+	return nil
+}
+
+// elemStruct returns the struct type of t, or of the elements or pointers of
+// t, or nil.
+func elemStruct(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// embeddedStruct returns the struct type of an embedded field, which can be
+// set, or nil.
+func embeddedStruct(f reflect.StructField) reflect.Type {
+	if !f.Anonymous {
+		return nil
+	}
+	t := f.Type
+	if t.Kind() == reflect.Ptr {
+		if f.PkgPath != "" {
+			// an unexported pointer cannot be allocated
+			return nil
+		}
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// hasTags tells if the struct type t has gjson tags.
+func hasTags(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if tag := f.Tag.Get("gjson"); tag != "" {
+			if tag != "-" {
+				return true
+			}
+		} else if st := embeddedStruct(f); st != nil && hasTags(st) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	resultType = reflect.TypeOf(Result{})
+	timeType   = reflect.TypeOf(time.Time{})
+	numberType = reflect.TypeOf(json.Number(""))
+)
+
+// conv returns the converter of the type t.
+func (b *planBuilder) conv(t reflect.Type) extractConv {
+	switch t {
+	case resultType:
+		return func(res Result, v reflect.Value) error {
+			v.Set(reflect.ValueOf(res))
+			return nil
+		}
+	case timeType:
+		return func(res Result, v reflect.Value) error {
+			v.Set(reflect.ValueOf(res.Time()))
+			return nil
+		}
+	case numberType:
+		return func(res Result, v reflect.Value) error {
+			v.SetString(string(res.Number()))
+			return nil
+		}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return func(res Result, v reflect.Value) error {
+			v.SetString(res.String())
+			return nil
+		}
+	case reflect.Bool:
+		return func(res Result, v reflect.Value) error {
+			v.SetBool(res.Bool())
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(res Result, v reflect.Value) error {
+			n := res.Int()
+			if v.OverflowInt(n) {
+				return ErrOverflow
+			}
+			v.SetInt(n)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(res Result, v reflect.Value) error {
+			n := res.Uint()
+			if v.OverflowUint(n) {
+				return ErrOverflow
+			}
+			v.SetUint(n)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(res Result, v reflect.Value) error {
+			f := res.Float()
+			if v.OverflowFloat(f) {
+				return ErrOverflow
+			}
+			v.SetFloat(f)
+			return nil
+		}
+	case reflect.Ptr:
+		elem := b.conv(t.Elem())
+		return func(res Result, v reflect.Value) error {
+			if v.IsNil() {
+				v.Set(reflect.New(t.Elem()))
+			}
+			return elem(res, v.Elem())
+		}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// base64 of []byte
+			break
+		}
+		elem := b.conv(t.Elem())
+		return func(res Result, v reflect.Value) error {
+			arr := res.Array()
+			s := reflect.MakeSlice(t, len(arr), len(arr))
+			for i, res := range arr {
+				if res.Type == Null {
+					continue
+				}
+				if err := elem(res, s.Index(i)); err != nil {
+					return err
+				}
+			}
+			v.Set(s)
+			return nil
+		}
+	case reflect.Struct:
+		if hasTags(t) {
+			p := b.plan(t)
+			return func(res Result, v reflect.Value) error {
+				return p.extract(res.Raw, v)
+			}
+		}
+	}
+	return func(res Result, v reflect.Value) error {
+		return res.Unmarshal(v.Addr().Interface())
+	}
+}
@@ -0,0 +1,155 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type extractFriend struct {
+	First string   `gjson:"first"`
+	Age   int8     `gjson:"age"`
+	Nets  []string `gjson:"nets"`
+}
+
+type extractBase struct {
+	Age int `gjson:"age"`
+}
+
+type extractNode struct {
+	Value int          `gjson:"value"`
+	Next  *extractNode `gjson:"next"`
+}
+
+// ExtractEmbedded is exported to be embedded as a pointer.
+type ExtractEmbedded struct {
+	Kind string `gjson:"kind"`
+}
+
+func TestExtract(t *testing.T) {
+	type named struct {
+		First string `json:"first"`
+	}
+	var v struct {
+		extractBase
+		*extractNode
+		Last      string            `gjson:"name.last"`
+		Children  []string          `gjson:"children"`
+		Count     uint              `gjson:"children.#"`
+		Murphys   []string          `gjson:"friends.#(last==\"Murphy\")#.first"`
+		Reversed  []string          `gjson:"children|@reverse"`
+		Multi     []interface{}     `gjson:"[name.first,age]"`
+		Friends   []extractFriend   `gjson:"friends"`
+		Best      *extractFriend    `gjson:"friends.1"`
+		Name      named             `gjson:"name"`
+		Raw       Result            `gjson:"fav\\.movie"`
+		Map       map[string]string `gjson:"name"`
+		Missing   string            `gjson:"missing"`
+		Skipped   string            `gjson:"-"`
+		Untagged  string
+		unexposed string `gjson:"age"`
+	}
+	v.Missing = "kept"
+	err := Extract(readmeJSON, &v)
+	assert(t, err == nil)
+	assert(t, v.Age == 37 && v.extractNode == nil && v.Last == "Anderson" && v.Count == 3)
+	assert(t, reflect.DeepEqual(v.Children, []string{"Sara", "Alex", "Jack"}))
+	assert(t, reflect.DeepEqual(v.Murphys, []string{"Dale", "Jane"}))
+	assert(t, reflect.DeepEqual(v.Reversed, []string{"Jack", "Alex", "Sara"}))
+	assert(t, reflect.DeepEqual(v.Multi, []interface{}{"Tom", float64(37)}))
+	assert(t, len(v.Friends) == 3 && v.Friends[2].First == "Jane" && v.Friends[2].Age == 47 && len(v.Friends[2].Nets) == 2)
+	assert(t, v.Best != nil && v.Best.First == "Roger" && v.Best.Age == 68)
+	assert(t, v.Name.First == "Tom" && v.Raw.String() == "Deer Hunter" && v.Map["last"] == "Anderson")
+	assert(t, v.Missing == "kept" && v.Skipped == "" && v.Untagged == "" && v.unexposed == "")
+
+	// recursive structs, and embedded pointers
+	var n struct {
+		extractNode
+		*ExtractEmbedded
+		When   time.Time   `gjson:"when"`
+		Amount json.Number `gjson:"amount"`
+		Ptr    *int        `gjson:"ptr"`
+	}
+	n.Ptr = new(int)
+	err = Extract(`{"kind":"list","value":1,"next":{"value":2,"next":{"value":3,"next":null}},
+		"when":"2024-03-01T10:30:45Z","amount":12345678901234567.89,"ptr":null}`, &n)
+	assert(t, err == nil && n.Value == 1 && n.Next.Value == 2 && n.Next.Next.Value == 3 && n.Next.Next.Next == nil)
+	assert(t, n.When.Equal(time.Date(2024, 3, 1, 10, 30, 45, 0, time.UTC)))
+	assert(t, n.Amount == "12345678901234567.89" && n.Ptr == nil && n.Kind == "list")
+
+	// the plan is cached
+	p1, _ := extractPlans.Load(reflect.TypeOf(extractNode{}))
+	p2 := extractPlanOf(reflect.TypeOf(extractNode{}))
+	assert(t, p1 != nil && p1.(*extractPlan) == p2)
+}
+
+func TestExtractErrors(t *testing.T) {
+	var s struct {
+		A int `gjson:"a"`
+	}
+	assert(t, Extract(`{}`, s) == errExtractTarget)
+	assert(t, Extract(`{}`, (*struct{})(nil)) == errExtractTarget)
+	var n int
+	assert(t, Extract(`{}`, &n) == errExtractTarget)
+
+	var fe *FieldError
+	var pe *PathSyntaxError
+	var bad struct {
+		A int `gjson:"a.#(b==1"`
+	}
+	err := Extract(`{}`, &bad)
+	assert(t, errors.As(err, &fe) && fe.Field == "A" && errors.As(err, &pe))
+
+	// the tags are checked like ValidatePath does
+	var junk struct {
+		A int `gjson:"a"`
+		B int `gjson:"b.#(c>1)#junk"`
+	}
+	err = Extract(`{"a":1}`, &junk)
+	assert(t, errors.As(err, &fe) && fe.Field == "B" && errors.As(err, &pe) && pe.Offset == 9)
+	var op struct {
+		A int `gjson:"friends.#(age=>40).age"`
+	}
+	err = Extract(`{}`, &op)
+	assert(t, errors.As(err, &fe) && fe.Field == "A" && errors.As(err, &pe) && pe.Msg == `invalid operator "=>"`)
+
+	// the errors of nested structs are reported even if they are not found
+	var nested struct {
+		Inner []struct {
+			B int `gjson:"[b"`
+		} `gjson:"inner"`
+	}
+	err = Extract(`{}`, &nested)
+	assert(t, errors.As(err, &fe) && fe.Field == "Inner" && errors.As(err, &pe))
+
+	var friends struct {
+		Friends []extractFriend `gjson:"friends"`
+	}
+	err = Extract(`{"friends":[{"age":1},{"age":200}]}`, &friends)
+	assert(t, errors.Is(err, ErrOverflow))
+	assert(t, err.Error() == `gjson: cannot extract field Friends from path "friends": cannot extract field Age from path "age": number out of range`)
+
+	var m struct {
+		M map[string]int `gjson:"m"`
+	}
+	err = Extract(`{"m":{"a":"x"}}`, &m)
+	assert(t, errors.As(err, &fe) && fe.Field == "M" && fe.Path == "m")
+}
@@ -2,6 +2,8 @@
 package gjson
 
 import (
+	"encoding/binary"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -263,12 +265,12 @@ func (t Result) ForEach(iterator func(key, value Result) bool) {
 			var str, val string
 			var vesc bool
 			s := i
-			i, val, str, vesc, ok = parseString(json, i)
+			i, val, str, vesc, ok = parseString(json, i, nil)
 			if !ok {
 				return
 			}
 			if vesc {
-				key.Str = unescape(str)
+				key.Str = unescape(str, nil)
 			} else {
 				key.Str = str
 			}
@@ -284,7 +286,7 @@ func (t Result) ForEach(iterator func(key, value Result) bool) {
 			break
 		}
 		s := i
-		i, value, ok = parseAny(json, i, true)
+		i, value, ok = parseAny(json, i, true, nil)
 		if !ok {
 			return
 		}
@@ -315,7 +317,16 @@ func (t Result) Map() map[string]Result {
 // Get searches result for the specified path.
 // The result should be a JSON array or object.
 func (t Result) Get(path string) Result {
-	r := Get(t.Raw, path)
+	return t.getWith(path, nil)
+}
+
+func (t Result) getWith(path string, opts *Options) Result {
+	return t.relative(getWith(t.Raw, path, opts))
+}
+
+// relative converts the indexes of r, which was searched from t.Raw, to be
+// relative to the json that t came from.
+func (t Result) relative(r Result) Result {
 	if r.Indexes != nil {
 		for i := 0; i < len(r.Indexes); i++ {
 			r.Indexes[i] += t.Index
@@ -411,7 +422,7 @@ func (t Result) arrayOrMap(vc byte, valueize bool) (r arrayOrMapResult) {
 			value.Str, value.Num = "", 0
 		case '"':
 			value.Type = String
-			value.Raw, value.Str = tostr(json[i:])
+			value.Raw, value.Str = tostr(json[i:], nil)
 			value.Num = 0
 		}
 		value.Index = i + t.Index
@@ -463,6 +474,10 @@ end:
 // If you are consuming JSON from an unpredictable source then you may want to
 // use the Valid function first.
 func Parse(json string) Result {
+	return parse(json, nil)
+}
+
+func parse(json string, opts *Options) Result {
 	var value Result
 	i := 0
 	for ; i < len(json); i++ {
@@ -497,7 +512,7 @@ func Parse(json string) Result {
 			value.Raw = tolit(json[i:])
 		case '"':
 			value.Type = String
-			value.Raw, value.Str = tostr(json[i:])
+			value.Raw, value.Str = tostr(json[i:], opts)
 		default:
 			return Result{}
 		}
@@ -600,9 +615,9 @@ func tolit(json string) (raw string) {
 	return json
 }
 
-func tostr(json string) (raw string, str string) {
-	if fast.FastStringEnable {
-		e, str, esc, err := fast.String(json, 0)
+func tostr(json string, opts *Options) (raw string, str string) {
+	if opts.fastString() {
+		e, str, esc, err := fast.String(json, 0, opts.validateUTF8())
 		if err != nil {
 			return json, ""
 		}
@@ -639,7 +654,7 @@ func tostr(json string) (raw string, str string) {
 							continue
 						}
 					}
-					return json[:i+1], unescape(json[1:i])
+					return json[:i+1], unescape(json[1:i], opts)
 				}
 			}
 			var ret string
@@ -648,7 +663,7 @@ func tostr(json string) (raw string, str string) {
 			} else {
 				ret = json[:i]
 			}
-			return ret, unescape(json[1:i])
+			return ret, unescape(json[1:i], opts)
 		}
 	}
 	return json, json[1:]
@@ -696,9 +711,9 @@ func (t Result) Value() interface{} {
 	}
 }
 
-func parseString(json string, i int) (int, string, string, bool, bool) {
-	if fast.FastStringEnable {
-		e, v, hasEsc, err := fast.String(json, i)
+func parseString(json string, i int, opts *Options) (int, string, string, bool, bool) {
+	if opts.fastString() {
+		e, v, hasEsc, err := fast.String(json, i, opts.validateUTF8())
 		return e, json[i:e], v, hasEsc, err == nil
 	}
 	i += 1
@@ -777,10 +792,16 @@ type arrayPathResult struct {
 		path  string
 		op    string
 		value string
+		// ref is a reference on the right of the op, which overrides value
+		ref *queryRef
+		// re is the compiled value of the =~ and !~ ops
+		re *regexp.Regexp
+		// expr is a compound query, which overrides path, op and value
+		expr *queryExpr
 	}
 }
 
-func parseArrayPath(path string) (r arrayPathResult) {
+func parseArrayPath(path string, opts *Options) (r arrayPathResult) {
 	for i := 0; i < len(path); i++ {
 		if path[i] == '|' {
 			r.part = path[:i]
@@ -790,7 +811,7 @@ func parseArrayPath(path string) (r arrayPathResult) {
 		}
 		if path[i] == '.' {
 			r.part = path[:i]
-			if !r.arrch && i < len(path)-1 && isDotPiperChar(path[i+1:]) {
+			if !r.arrch && i < len(path)-1 && isDotPiperChar(path[i+1:], opts) {
 				r.pipe = path[i+1:]
 				r.piped = true
 			} else {
@@ -815,16 +836,14 @@ func parseArrayPath(path string) (r arrayPathResult) {
 						// bad query, end now
 						break
 					}
-					if len(value) >= 2 && value[0] == '"' &&
-						value[len(value)-1] == '"' {
-						value = value[1 : len(value)-1]
-						if vesc {
-							value = unescape(value)
-						}
-					}
 					r.query.path = qpath
 					r.query.op = op
-					r.query.value = value
+					r.query.value = queryValue(value, vesc, opts)
+					r.query.ref = parseQueryRef(value)
+					r.query.re = queryRegexp(op, r.query.value)
+					if isCompoundQuery(path[i+2 : fi-1]) {
+						r.query.expr = parseQueryExpr(path[i+2:fi-1], opts)
+					}
 
 					i = fi - 1
 					if i+1 < len(path) && path[i+1] == '#' {
@@ -907,37 +926,20 @@ func parseQuery(query string) (
 	if depth > 0 {
 		return "", "", "", "", i, false, false
 	}
-	if j > 0 {
+	if ws, we := findWordOp(query[2:i]); ws >= 0 {
+		path = trim(query[2 : 2+ws])
+		op = query[2+ws : 2+we]
+		value = trim(query[2+we : i])
+		remain = query[i+1:]
+	} else if j > 0 {
 		path = trim(query[2:j])
-		value = trim(query[j:i])
+		op, value = parseQueryOp(trim(query[j:i]))
+		remain = query[i+1:]
+	} else if k := findTypeOp(query[2:i]); k >= 0 {
+		path = trim(query[2 : 2+k])
+		op = ":"
+		value = trim(query[3+k : i])
 		remain = query[i+1:]
-		// parse the compare op from the value
-		var opsz int
-		switch {
-		case len(value) == 1:
-			opsz = 1
-		case value[0] == '!' && value[1] == '=':
-			opsz = 2
-		case value[0] == '!' && value[1] == '%':
-			opsz = 2
-		case value[0] == '<' && value[1] == '=':
-			opsz = 2
-		case value[0] == '>' && value[1] == '=':
-			opsz = 2
-		case value[0] == '=' && value[1] == '=':
-			value = value[1:]
-			opsz = 1
-		case value[0] == '<':
-			opsz = 1
-		case value[0] == '>':
-			opsz = 1
-		case value[0] == '=':
-			opsz = 1
-		case value[0] == '%':
-			opsz = 1
-		}
-		op = value[:opsz]
-		value = trim(value[opsz:])
 	} else {
 		path = trim(query[2:i])
 		remain = query[i+1:]
@@ -945,6 +947,50 @@ func parseQuery(query string) (
 	return path, op, value, remain, i + 1, vesc, true
 }
 
+// queryValue strips the quotes of a string value of a query.
+func queryValue(value string, vesc bool, opts *Options) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = value[1 : len(value)-1]
+		if vesc {
+			value = unescape(value, opts)
+		}
+	}
+	return value
+}
+
+// parseQueryOp parses the compare op from the value part of a query, which
+// starts with an op character.
+func parseQueryOp(value string) (op, rest string) {
+	var opsz int
+	switch {
+	case len(value) == 1:
+		opsz = 1
+	case (value[0] == '=' || value[0] == '!') && value[1] == '~' &&
+		isRegexpValue(value[2:]):
+		opsz = 2
+	case value[0] == '!' && value[1] == '=':
+		opsz = 2
+	case value[0] == '!' && value[1] == '%':
+		opsz = 2
+	case value[0] == '<' && value[1] == '=':
+		opsz = 2
+	case value[0] == '>' && value[1] == '=':
+		opsz = 2
+	case value[0] == '=' && value[1] == '=':
+		value = value[1:]
+		opsz = 1
+	case value[0] == '<':
+		opsz = 1
+	case value[0] == '>':
+		opsz = 1
+	case value[0] == '=':
+		opsz = 1
+	case value[0] == '%':
+		opsz = 1
+	}
+	return value[:opsz], trim(value[opsz:])
+}
+
 func trim(s string) string {
 left:
 	if len(s) > 0 && s[0] <= ' ' {
@@ -960,8 +1006,8 @@ right:
 }
 
 // peek at the next byte and see if it's a '@', '[', or '{'.
-func isDotPiperChar(s string) bool {
-	if DisableModifiers {
+func isDotPiperChar(s string, opts *Options) bool {
+	if !opts.modifiers() {
 		return false
 	}
 	c := s[0]
@@ -988,7 +1034,7 @@ type objectPathResult struct {
 	more  bool
 }
 
-func parseObjectPath(path string) (r objectPathResult) {
+func parseObjectPath(path string, opts *Options) (r objectPathResult) {
 	for i := 0; i < len(path); i++ {
 		if path[i] == '|' {
 			r.part = path[:i]
@@ -998,7 +1044,7 @@ func parseObjectPath(path string) (r objectPathResult) {
 		}
 		if path[i] == '.' {
 			r.part = path[:i]
-			if i < len(path)-1 && isDotPiperChar(path[i+1:]) {
+			if i < len(path)-1 && isDotPiperChar(path[i+1:], opts) {
 				r.pipe = path[i+1:]
 				r.piped = true
 			} else {
@@ -1028,7 +1074,7 @@ func parseObjectPath(path string) (r objectPathResult) {
 						continue
 					} else if path[i] == '.' {
 						r.part = string(epart)
-						if i < len(path)-1 && isDotPiperChar(path[i+1:]) {
+						if i < len(path)-1 && isDotPiperChar(path[i+1:], opts) {
 							r.pipe = path[i+1:]
 							r.piped = true
 						} else {
@@ -1063,13 +1109,22 @@ func parseSquash(json string, i int) (int, string) {
 	return fast.Skip(json, i)
 }
 
-func parseObject(c *parseContext, i int, path string) (int, bool) {
+func parseObject(c *parseContext, i int, path string, n *pathNode) (int, bool) {
 	var pmatch, kesc, ok, hit bool
 	var key, val string
-	rp := parseObjectPath(path)
+	var rp objectPathResult
+	var next *pathNode
+	if n != nil {
+		rp, next = n.obj, n.objNext
+	} else {
+		rp = parseObjectPath(path, c.opts)
+	}
 	if !rp.more && rp.piped {
 		c.pipe = rp.pipe
 		c.piped = true
+		if n != nil {
+			c.pipePath = n.objPipe
+		}
 	}
 	for i < len(c.json) {
 		for ; i < len(c.json); i++ {
@@ -1127,13 +1182,13 @@ func parseObject(c *parseContext, i int, path string) (int, bool) {
 		}
 		if rp.wild {
 			if kesc {
-				pmatch = matchLimit(unescape(key), rp.part)
+				pmatch = matchLimit(unescape(key, c.opts), rp.part, c.opts)
 			} else {
-				pmatch = matchLimit(key, rp.part)
+				pmatch = matchLimit(key, rp.part, c.opts)
 			}
 		} else {
 			if kesc {
-				pmatch = rp.part == unescape(key)
+				pmatch = rp.part == unescape(key, c.opts)
 			} else {
 				pmatch = rp.part == key
 			}
@@ -1147,13 +1202,13 @@ func parseObject(c *parseContext, i int, path string) (int, bool) {
 			case '"':
 				var str string
 				var vesc bool
-				i, val, str, vesc, ok = parseString(c.json, i)
+				i, val, str, vesc, ok = parseString(c.json, i, c.opts)
 				if !ok {
 					return i, false
 				}
 				if hit {
 					if vesc {
-						c.value.Str = unescape(str)
+						c.value.Str = unescape(str, c.opts)
 					} else {
 						c.value.Str = str
 					}
@@ -1163,7 +1218,7 @@ func parseObject(c *parseContext, i int, path string) (int, bool) {
 				}
 			case '{':
 				if pmatch && !hit {
-					i, hit = parseObject(c, i+1, rp.path)
+					i, hit = parseObject(c, i+1, rp.path, next)
 					if hit {
 						return i, true
 					}
@@ -1177,7 +1232,7 @@ func parseObject(c *parseContext, i int, path string) (int, bool) {
 				}
 			case '[':
 				if pmatch && !hit {
-					i, hit = parseArray(c, i+1, rp.path)
+					i, hit = parseArray(c, i+1, rp.path, next)
 					if hit {
 						return i, true
 					}
@@ -1230,8 +1285,8 @@ func parseObject(c *parseContext, i int, path string) (int, bool) {
 // matchLimit will limit the complexity of the match operation to avoid ReDos
 // attacks from arbitrary inputs.
 // See the github.com/tidwall/match.MatchLimit function for more information.
-func matchLimit(str, pattern string) bool {
-	matched, _ := match.MatchLimit(str, pattern, 10000)
+func matchLimit(str, pattern string, opts *Options) bool {
+	matched, _ := match.MatchLimit(str, pattern, opts.matchLimit())
 	return matched
 }
 
@@ -1275,8 +1330,7 @@ func nullish(t Result) bool {
 	return t.Type == Null
 }
 
-func queryMatches(rp *arrayPathResult, value Result) bool {
-	rpv := rp.query.value
+func queryMatches(op, rpv string, value Result, opts *Options) bool {
 	if len(rpv) > 0 {
 		if rpv[0] == '~' {
 			// convert to bool
@@ -1305,19 +1359,39 @@ func queryMatches(rp *arrayPathResult, value Result) bool {
 			}
 		}
 	}
+	return compareQuery(op, rpv, value, opts)
+}
+
+// compareQuery compares value to the value rpv on the right of op.
+func compareQuery(op, rpv string, value Result, opts *Options) bool {
 	if !value.Exists() {
 		return false
 	}
-	if rp.query.op == "" {
+	if op == "" {
 		// the query is only looking for existence, such as:
 		//   friends.#(name)
 		// which makes sure that the array "friends" has an element of
 		// "name" that exists
 		return true
 	}
+	switch op {
+	case "in", "!in", "contains", "!contains", "startsWith", "endsWith":
+		return wordMatches(op, rpv, value, opts)
+	case ":":
+		return typeMatches(rpv, value)
+	}
 	switch value.Type {
 	case String:
-		switch rp.query.op {
+		if opts.chronologicalTimes() {
+			switch op {
+			case "<", "<=", ">", ">=":
+				if c, ok := compareTimes(value.Str, rpv); ok {
+					match, _ := compareMatches(op, c)
+					return match
+				}
+			}
+		}
+		switch op {
 		case "=":
 			return value.Str == rpv
 		case "!=":
@@ -1331,13 +1405,20 @@ func queryMatches(rp *arrayPathResult, value Result) bool {
 		case ">=":
 			return value.Str >= rpv
 		case "%":
-			return matchLimit(value.Str, rpv)
+			return matchLimit(value.Str, rpv, opts)
 		case "!%":
-			return !matchLimit(value.Str, rpv)
+			return !matchLimit(value.Str, rpv, opts)
 		}
 	case Number:
+		if opts.exactNumbers() {
+			if c, ok := compareNumbers(value.Raw, rpv); ok {
+				if match, ok := compareMatches(op, c); ok {
+					return match
+				}
+			}
+		}
 		rpvn, _ := strconv.ParseFloat(rpv, 64)
-		switch rp.query.op {
+		switch op {
 		case "=":
 			return value.Num == rpvn
 		case "!=":
@@ -1352,7 +1433,7 @@ func queryMatches(rp *arrayPathResult, value Result) bool {
 			return value.Num >= rpvn
 		}
 	case True:
-		switch rp.query.op {
+		switch op {
 		case "=":
 			return rpv == "true"
 		case "!=":
@@ -1363,7 +1444,7 @@ func queryMatches(rp *arrayPathResult, value Result) bool {
 			return true
 		}
 	case False:
-		switch rp.query.op {
+		switch op {
 		case "=":
 			return rpv == "false"
 		case "!=":
@@ -1376,7 +1457,28 @@ func queryMatches(rp *arrayPathResult, value Result) bool {
 	}
 	return false
 }
-func parseArray(c *parseContext, i int, path string) (int, bool) {
+
+// compareMatches tells if the result c of comparing a value with the value of
+// a query matches the op, and returns false if op is not a comparison.
+func compareMatches(op string, c int) (match, ok bool) {
+	switch op {
+	case "=":
+		return c == 0, true
+	case "!=":
+		return c != 0, true
+	case "<":
+		return c < 0, true
+	case "<=":
+		return c <= 0, true
+	case ">":
+		return c > 0, true
+	case ">=":
+		return c >= 0, true
+	}
+	return false, false
+}
+
+func parseArray(c *parseContext, i int, path string, n *pathNode) (int, bool) {
 	var pmatch, ok, hit bool
 	var val string
 	var h int
@@ -1384,7 +1486,13 @@ func parseArray(c *parseContext, i int, path string) (int, bool) {
 	var partidx int
 	var multires []byte
 	var queryIndexes []int
-	rp := parseArrayPath(path)
+	var rp arrayPathResult
+	var next *pathNode
+	if n != nil {
+		rp, next = n.arr, n.arrNext
+	} else {
+		rp = parseArrayPath(path, c.opts)
+	}
 	if !rp.arrch {
 		n, ok := parseUint(rp.part)
 		if !ok {
@@ -1396,6 +1504,9 @@ func parseArray(c *parseContext, i int, path string) (int, bool) {
 	if !rp.more && rp.piped {
 		c.pipe = rp.pipe
 		c.piped = true
+		if n != nil {
+			c.pipePath = n.arrPipe
+		}
 	}
 
 	procQuery := func(qval Result) bool {
@@ -1409,23 +1520,52 @@ func parseArray(c *parseContext, i int, path string) (int, bool) {
 		fillIndex(c.json, &tmp)
 		parentIndex := tmp.value.Index
 		var res Result
-		if qval.Type == JSON {
-			res = qval.Get(rp.query.path)
+		var matched bool
+		if rp.query.expr != nil {
+			matched = rp.query.expr.matches(qval, c.json, c.opts)
 		} else {
-			if rp.query.path != "" {
+			switch {
+			case rp.query.path == "" && rp.query.op == ":":
+				// the element itself, such as #(:object)
+				res = qval
+			case qval.Type == JSON:
+				if n != nil {
+					res = qval.getPath(n.query)
+				} else {
+					res = qval.getWith(rp.query.path, c.opts)
+				}
+			case rp.query.path != "":
 				return false
+			default:
+				res = qval
+			}
+			switch {
+			case rp.query.re != nil:
+				matched = regexpMatches(rp.query.op, rp.query.re, res)
+			case rp.query.ref != nil:
+				matched = refMatches(rp.query.op, rp.query.ref.resolve(qval, c.json, c.opts), res, c.opts)
+			default:
+				matched = queryMatches(rp.query.op, rp.query.value, res, c.opts)
 			}
-			res = qval
 		}
-		if queryMatches(&rp, res) {
+		if matched {
 			if rp.more {
-				left, right, ok := splitPossiblePipe(rp.path)
-				if ok {
-					rp.path = left
-					c.pipe = right
-					c.piped = true
+				if n != nil {
+					if n.morePipe != nil {
+						c.pipe = n.morePipe.path
+						c.pipePath = n.morePipe
+						c.piped = true
+					}
+					res = qval.getPath(n.more)
+				} else {
+					left, right, ok := splitPossiblePipe(rp.path)
+					if ok {
+						rp.path = left
+						c.pipe = right
+						c.piped = true
+					}
+					res = qval.getWith(rp.path, c.opts)
 				}
-				res = qval.Get(rp.path)
 			} else {
 				res = qval
 			}
@@ -1473,14 +1613,14 @@ func parseArray(c *parseContext, i int, path string) (int, bool) {
 			case '"':
 				var str string
 				var vesc bool
-				i, val, str, vesc, ok = parseString(c.json, i)
+				i, val, str, vesc, ok = parseString(c.json, i, c.opts)
 				if !ok {
 					return i, false
 				}
 				if rp.query.on {
 					var qval Result
 					if vesc {
-						qval.Str = unescape(str)
+						qval.Str = unescape(str, c.opts)
 					} else {
 						qval.Str = str
 					}
@@ -1494,7 +1634,7 @@ func parseArray(c *parseContext, i int, path string) (int, bool) {
 						break
 					}
 					if vesc {
-						c.value.Str = unescape(val[1 : len(val)-1])
+						c.value.Str = unescape(val[1:len(val)-1], c.opts)
 					} else {
 						c.value.Str = val[1 : len(val)-1]
 					}
@@ -1504,7 +1644,7 @@ func parseArray(c *parseContext, i int, path string) (int, bool) {
 				}
 			case '{':
 				if pmatch && !hit {
-					i, hit = parseObject(c, i+1, rp.path)
+					i, hit = parseObject(c, i+1, rp.path, next)
 					if hit {
 						if rp.alogok {
 							break
@@ -1528,7 +1668,7 @@ func parseArray(c *parseContext, i int, path string) (int, bool) {
 				}
 			case '[':
 				if pmatch && !hit {
-					i, hit = parseArray(c, i+1, rp.path)
+					i, hit = parseArray(c, i+1, rp.path, next)
 					if hit {
 						if rp.alogok {
 							break
@@ -1590,11 +1730,19 @@ func parseArray(c *parseContext, i int, path string) (int, bool) {
 			case ']':
 				if rp.arrch && rp.part == "#" {
 					if rp.alogok {
-						left, right, ok := splitPossiblePipe(rp.alogkey)
-						if ok {
-							rp.alogkey = left
-							c.pipe = right
-							c.piped = true
+						if n != nil {
+							if n.alogPipe != nil {
+								c.pipe = n.alogPipe.path
+								c.pipePath = n.alogPipe
+								c.piped = true
+							}
+						} else {
+							left, right, ok := splitPossiblePipe(rp.alogkey)
+							if ok {
+								rp.alogkey = left
+								c.pipe = right
+								c.piped = true
+							}
 						}
 						var indexes = make([]int, 0, 64)
 						var jsons = make([]byte, 0, 64)
@@ -1610,9 +1758,13 @@ func parseArray(c *parseContext, i int, path string) (int, bool) {
 								break
 							}
 							if idx < len(c.json) && c.json[idx] != ']' {
-								_, res, ok := parseAny(c.json, idx, true)
+								_, res, ok := parseAny(c.json, idx, true, c.opts)
 								if ok {
-									res := res.Get(rp.alogkey)
+									if n != nil {
+										res = res.getPath(n.alog)
+									} else {
+										res = res.getWith(rp.alogkey, c.opts)
+									}
 									if res.Exists() {
 										if k > 0 {
 											jsons = append(jsons, ',')
@@ -1775,7 +1927,7 @@ func ForEachLine(json string, iterator func(line Result) bool) {
 	var res Result
 	var i int
 	for {
-		i, res, _ = parseAny(json, i, true)
+		i, res, _ = parseAny(json, i, true, nil)
 		if !res.Exists() {
 			break
 		}
@@ -1907,6 +2059,10 @@ var DisableEscapeHTML = false
 // AppendJSONString is a convenience function that converts the provided string
 // to a valid JSON string and appends it to dst.
 func AppendJSONString(dst []byte, s string) []byte {
+	return appendJSONString(dst, s, nil)
+}
+
+func appendJSONString(dst []byte, s string, opts *Options) []byte {
 	dst = append(dst, make([]byte, len(s)+2)...)
 	dst = append(dst[:len(dst)-len(s)-2], '"')
 	for i := 0; i < len(s); i++ {
@@ -1927,7 +2083,7 @@ func AppendJSONString(dst []byte, s string) []byte {
 				dst = append(dst, 'u')
 				dst = appendHex16(dst, uint16(s[i]))
 			}
-		} else if !DisableEscapeHTML &&
+		} else if opts.escapeHTML() &&
 			(s[i] == '>' || s[i] == '<' || s[i] == '&') {
 			dst = append(dst, '\\', 'u')
 			dst = appendHex16(dst, uint16(s[i]))
@@ -1958,12 +2114,14 @@ func AppendJSONString(dst []byte, s string) []byte {
 }
 
 type parseContext struct {
-	json  string
-	value Result
-	pipe  string
-	piped bool
-	calcd bool
-	lines bool
+	json     string
+	value    Result
+	pipe     string
+	pipePath *Path
+	piped    bool
+	calcd    bool
+	lines    bool
+	opts     *Options
 }
 
 // Get searches json for the specified path.
@@ -2000,27 +2158,31 @@ type parseContext struct {
 // If you are consuming JSON from an unpredictable source then you may want to
 // use the Valid function first.
 func Get(json, path string) Result {
+	return getWith(json, path, nil)
+}
+
+func getWith(json, path string, opts *Options) Result {
 	// fast-path: check if the path is simple and use fast.Get() function
-	if paths := fast.FastPaths(path); paths != nil {
-		s, e, t, err := fast.Get(json, paths...)
-		if err == nil {
-			ret := Result{Raw: json[s:e], Type: Type(fast.JSONType(t)), Index: s}
-			switch ret.Type {
-			case Number:
-				ret.Num, _ = strconv.ParseFloat(ret.Raw, 64)
-			case String:
-				_, ret.Str = tostr(ret.Raw)
+	if opts.fastPath() {
+		if paths, prefix, rest := fast.FastPaths(path); paths != nil {
+			if ret, ok := getFast(json, paths, opts); ok {
+				return ret
+			}
+		} else if prefix != nil {
+			// hybrid-path: search the literal prefix using fast.Get() function,
+			// and the rest of the path from the found value
+			if ret, ok := getFastPrefix(json, prefix, rest, nil, opts); ok {
+				return ret
 			}
-			return ret
 		}
 	}
 	if len(path) > 1 {
-		if (path[0] == '@' && !DisableModifiers) || path[0] == '!' {
+		if (path[0] == '@' && opts.modifiers()) || path[0] == '!' {
 			// possible modifier
 			var ok bool
 			var npath string
 			var rjson string
-			if path[0] == '@' && !DisableModifiers {
+			if path[0] == '@' && opts.modifiers() {
 				npath, rjson, ok = execModifier(json, path)
 			} else if path[0] == '!' {
 				npath, rjson, ok = execStatic(json, path)
@@ -2028,12 +2190,12 @@ func Get(json, path string) Result {
 			if ok {
 				path = npath
 				if len(path) > 0 && (path[0] == '|' || path[0] == '.') {
-					res := Get(rjson, path[1:])
+					res := getWith(rjson, path[1:], opts)
 					res.Index = 0
 					res.Indexes = nil
 					return res
 				}
-				return Parse(rjson)
+				return parse(rjson, opts)
 			}
 		}
 		if path[0] == '[' || path[0] == '{' {
@@ -2048,38 +2210,9 @@ func Get(json, path string) Result {
 					b = append(b, kind)
 					var i int
 					for _, sub := range subs {
-						res := Get(json, sub.path)
+						res := getWith(json, sub.path, opts)
 						if res.Exists() {
-							if i > 0 {
-								b = append(b, ',')
-							}
-							if kind == '{' {
-								if len(sub.name) > 0 {
-									if sub.name[0] == '"' && Valid(sub.name) {
-										b = append(b, sub.name...)
-									} else {
-										b = AppendJSONString(b, sub.name)
-									}
-								} else {
-									last := nameOfLast(sub.path)
-									if isSimpleName(last) {
-										b = AppendJSONString(b, last)
-									} else {
-										b = AppendJSONString(b, "_")
-									}
-								}
-								b = append(b, ':')
-							}
-							var raw string
-							if len(res.Raw) == 0 {
-								raw = res.String()
-								if len(raw) == 0 {
-									raw = "null"
-								}
-							} else {
-								raw = res.Raw
-							}
-							b = append(b, raw...)
+							b = appendSubSelection(b, kind, i, sub, res, opts)
 							i++
 						}
 					}
@@ -2088,7 +2221,7 @@ func Get(json, path string) Result {
 					res.Raw = string(b)
 					res.Type = JSON
 					if len(path) > 0 {
-						res = res.Get(path[1:])
+						res = res.getWith(path[1:], opts)
 					}
 					res.Index = 0
 					return res
@@ -2097,37 +2230,128 @@ func Get(json, path string) Result {
 		}
 	}
 	var i int
-	var c = &parseContext{json: json}
+	var c = &parseContext{json: json, opts: opts}
 	if len(path) >= 2 && path[0] == '.' && path[1] == '.' {
 		c.lines = true
-		parseArray(c, 0, path[2:])
+		parseArray(c, 0, path[2:], nil)
 	} else {
 		for ; i < len(c.json); i++ {
 			if c.json[i] == '{' {
 				i++
-				parseObject(c, i, path)
+				parseObject(c, i, path, nil)
 				break
 			}
 			if c.json[i] == '[' {
 				i++
-				parseArray(c, i, path)
+				parseArray(c, i, path, nil)
 				break
 			}
 		}
 	}
+	return c.result()
+}
+
+// result returns the value found by the context, evaluating the piped path
+// if there is one.
+func (c *parseContext) result() Result {
 	if c.piped {
-		res := c.value.Get(c.pipe)
+		var res Result
+		if c.pipePath != nil {
+			res = c.value.getPath(c.pipePath)
+		} else {
+			res = c.value.getWith(c.pipe, c.opts)
+		}
 		res.Index = 0
 		return res
 	}
-	fillIndex(json, c)
+	fillIndex(c.json, c)
 	return c.value
 }
 
+// getFast searches the parsed simple paths using fast.Get() function.
+func getFast(json string, paths []interface{}, opts *Options) (Result, bool) {
+	s, e, t, err := fast.Get(json, paths...)
+	if err != nil {
+		return Result{}, false
+	}
+	ret := Result{Raw: json[s:e], Type: Type(fast.JSONType(t)), Index: s}
+	switch ret.Type {
+	case Number:
+		ret.Num, _ = strconv.ParseFloat(ret.Raw, 64)
+	case String:
+		_, _, str, vesc, ok := parseString(ret.Raw, 0, opts)
+		if !ok {
+			return Result{}, false
+		}
+		if vesc {
+			str = unescape(str, opts)
+		}
+		ret.Str = str
+	}
+	return ret, true
+}
+
+// getFastPrefix searches the parsed literal prefix of a path using fast.Get()
+// function, and then searches the rest of the path inside the found value.
+// It reports false if the rest is not found, since the prefix may match
+// a duplicate key later in the json.
+func getFastPrefix(json string, prefix []interface{}, rest string, n *pathNode, opts *Options) (Result, bool) {
+	s, _, _, err := fast.Get(json, prefix...)
+	if err != nil {
+		return Result{}, false
+	}
+	var c = &parseContext{json: json, opts: opts}
+	switch json[s] {
+	case '{':
+		parseObject(c, s+1, rest, n)
+	case '[':
+		parseArray(c, s+1, rest, n)
+	default:
+		return Result{}, false
+	}
+	ret := c.result()
+	return ret, ret.Exists()
+}
+
+// appendSubSelection appends the result of a multipath member to b, which is
+// the JSON array or object being built for the multipath.
+func appendSubSelection(b []byte, kind byte, i int, sub subSelector, res Result, opts *Options) []byte {
+	if i > 0 {
+		b = append(b, ',')
+	}
+	if kind == '{' {
+		if len(sub.name) > 0 {
+			if sub.name[0] == '"' && Valid(sub.name) {
+				b = append(b, sub.name...)
+			} else {
+				b = appendJSONString(b, sub.name, opts)
+			}
+		} else {
+			last := nameOfLast(sub.path)
+			if isSimpleName(last) {
+				b = appendJSONString(b, last, opts)
+			} else {
+				b = appendJSONString(b, "_", opts)
+			}
+		}
+		b = append(b, ':')
+	}
+	var raw string
+	if len(res.Raw) == 0 {
+		raw = res.String()
+		if len(raw) == 0 {
+			raw = "null"
+		}
+	} else {
+		raw = res.Raw
+	}
+	return append(b, raw...)
+}
+
 // GetBytes searches json for the specified path.
 // If working with bytes, this method preferred over Get(string(data), path)
 func GetBytes(json []byte, path string) Result {
-	return getBytes(json, path)
+	return getBytes(json, path, nil)
 }
 
 // runeit returns the rune from the the \uXXXX
@@ -2137,8 +2361,8 @@ func runeit(json string) rune {
 }
 
 // unescape unescapes a string
-func unescape(json string) string {
-	if fast.FastStringEnable {
+func unescape(json string, opts *Options) string {
+	if opts.fastString() {
 		str, _ := fast.Unquote(json)
 		return str
 	}
@@ -2263,7 +2487,7 @@ func stringLessInsensitive(a, b string) bool {
 // parseAny parses the next value from a json string.
 // A Result is returned when the hit param is set.
 // The return values are (i int, res Result, ok bool)
-func parseAny(json string, i int, hit bool) (int, Result, bool) {
+func parseAny(json string, i int, hit bool, opts *Options) (int, Result, bool) {
 	var res Result
 	var val string
 	for ; i < len(json); i++ {
@@ -2286,7 +2510,7 @@ func parseAny(json string, i int, hit bool) (int, Result, bool) {
 		case '"':
 			var str string
 			var ok, vesc bool
-			i, val, str, vesc, ok = parseString(json, i)
+			i, val, str, vesc, ok = parseString(json, i, opts)
 			if !ok {
 				return i, res, false
 			}
@@ -2294,7 +2518,7 @@ func parseAny(json string, i int, hit bool) (int, Result, bool) {
 				res.Type = String
 				res.Raw = val
 				if vesc {
-					res.Str = unescape(str)
+					res.Str = unescape(str, opts)
 				} else {
 					res.Str = str
 				}
@@ -2337,28 +2561,6 @@ func parseAny(json string, i int, hit bool) (int, Result, bool) {
 	return i, res, false
 }
 
-// GetMany searches json for the multiple paths.
-// The return value is a Result array where the number of items
-// will be equal to the number of input paths.
-func GetMany(json string, path ...string) []Result {
-	res := make([]Result, len(path))
-	for i, path := range path {
-		res[i] = Get(json, path)
-	}
-	return res
-}
-
-// GetManyBytes searches json for the multiple paths.
-// The return value is a Result array where the number of items
-// will be equal to the number of input paths.
-func GetManyBytes(json []byte, path ...string) []Result {
-	res := make([]Result, len(path))
-	for i, path := range path {
-		res[i] = GetBytes(json, path)
-	}
-	return res
-}
-
 func validpayload(data []byte, i int) (outi int, ok bool) {
 	for ; i < len(data); i++ {
 		switch data[i] {
@@ -2638,11 +2840,8 @@ const fastValidThreshold = 1024 * 10
 //	}
 //	value := gjson.Get(json, "name.last")
 func Valid(json string) bool {
-	if len(json) < fastValidThreshold {
-		_, ok := validpayload(stringBytes(json), 0)
-		return ok
-	}
-	return fast.Valid(json)
+	_, ok := validjson(stringBytes(json))
+	return ok
 }
 
 // ValidBytes returns true if the input is valid json.
@@ -2654,11 +2853,99 @@ func Valid(json string) bool {
 //
 // If working with bytes, this method preferred over ValidBytes(string(data))
 func ValidBytes(json []byte) bool {
-	if len(json) < fastValidThreshold {
-		_, ok := validpayload(json, 0)
-		return ok
+	_, ok := validjson(json)
+	return ok
+}
+
+// validjson validates a large json using the native validator, which gives
+// the same results as validpayload does.
+func validjson(data []byte) (outi int, ok bool) {
+	if len(data) >= fastValidThreshold && fast.Valid(bytesString(data)) {
+		return validnative(data)
 	}
-	return fast.Valid(bytesString(json))
+	// the native validator also limits the depth of nesting to 4096,
+	// thus a json it rejects is validated again
+	return validpayload(data, 0)
+}
+
+// validnative checks a json accepted by the native validator, which does not
+// check the chars and escapes in strings, and accepts a minus sign without
+// digits. Plain chars are skipped 8 bytes at a time.
+func validnative(data []byte) (outi int, ok bool) {
+	i := 0
+	for {
+		for ; i+8 <= len(data); i += 8 {
+			x := binary.LittleEndian.Uint64(data[i:])
+			if hasbyte(x, '"')|hasbyte(x, '-') != 0 {
+				break
+			}
+		}
+		for ; i < len(data) && data[i] != '"' && data[i] != '-'; i++ {
+		}
+		if i == len(data) {
+			return i, true
+		}
+		i++
+		if data[i-1] == '-' {
+			if i == len(data) || data[i] < '0' || data[i] > '9' {
+				return i, false
+			}
+			continue
+		}
+		for {
+			for ; i+8 <= len(data); i += 8 {
+				x := binary.LittleEndian.Uint64(data[i:])
+				if hasless(x, ' ')|hasbyte(x, '"')|hasbyte(x, '\\') != 0 {
+					break
+				}
+			}
+			if i == len(data) {
+				return i, false
+			}
+			if c := data[i]; c == '"' {
+				i++
+				break
+			} else if c < ' ' {
+				return i, false
+			} else if c == '\\' {
+				if i++; i == len(data) {
+					return i, false
+				}
+				switch data[i] {
+				default:
+					return i, false
+				case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+				case 'u':
+					if i+4 >= len(data) || !ishex(data[i+1]) || !ishex(data[i+2]) ||
+						!ishex(data[i+3]) || !ishex(data[i+4]) {
+						return i, false
+					}
+					i += 4
+				}
+			}
+			i++
+		}
+	}
+}
+
+func ishex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+const (
+	lsb8 = 0x0101010101010101
+	msb8 = 0x8080808080808080
+)
+
+// hasbyte is non-zero if any byte of x is c
+func hasbyte(x uint64, c byte) uint64 {
+	x ^= lsb8 * uint64(c)
+	return (x - lsb8) &^ x & msb8
+}
+
+// hasless is non-zero if any byte of x is less than c, which is at most 128
+func hasless(x uint64, c byte) uint64 {
+	return (x - lsb8*uint64(c)) &^ x & msb8
 }
 
 func parseUint(s string) (n uint64, ok bool) {
@@ -2713,6 +3000,12 @@ func safeInt(f float64) (n int64, ok bool) {
 // execStatic parses the path to find a static value.
 // The input expects that the path already starts with a '!'
 func execStatic(json, path string) (pathOut, res string, ok bool) {
+	return parseStatic(path)
+}
+
+// parseStatic parses the static value and the remaining path from a path that
+// starts with a '!'.
+func parseStatic(path string) (pathOut, res string, ok bool) {
 	name := path[1:]
 	if len(name) > 0 {
 		switch name[0] {
@@ -2745,6 +3038,16 @@ func execStatic(json, path string) (pathOut, res string, ok bool) {
 // execModifier parses the path to find a matching modifier function.
 // The input expects that the path already starts with a '@'
 func execModifier(json, path string) (pathOut, res string, ok bool) {
+	pathOut, fn, args, ok := parseModifier(path)
+	if ok {
+		return pathOut, fn(json, args), true
+	}
+	return pathOut, res, false
+}
+
+// parseModifier parses the modifier function, its arguments and the remaining
+// path from a path that starts with a '@'.
+func parseModifier(path string) (pathOut string, fn func(json, arg string) string, args string, ok bool) {
 	name := path[1:]
 	var hasArgs bool
 	for i := 1; i < len(path); i++ {
@@ -2765,8 +3068,7 @@ func execModifier(json, path string) (pathOut, res string, ok bool) {
 			break
 		}
 	}
-	if fn, ok := modifiers[name]; ok {
-		var args string
+	if fn, ok = modifiers[name]; ok {
 		if hasArgs {
 			var parsedArgs bool
 			switch pathOut[0] {
@@ -2796,9 +3098,9 @@ func execModifier(json, path string) (pathOut, res string, ok bool) {
 				pathOut = pathOut[i:]
 			}
 		}
-		return pathOut, fn(json, args), true
+		return pathOut, fn, args, true
 	}
-	return pathOut, res, false
+	return pathOut, nil, "", false
 }
 
 // unwrap removes the '[]' or '{}' characters around json
@@ -3196,46 +3498,52 @@ type sliceHeader struct {
 // getBytes casts the input json bytes to a string and safely returns the
 // results as uniquely allocated data. This operation is intended to minimize
 // copies and allocations for the large json string->[]byte.
-func getBytes(json []byte, path string) Result {
+func getBytes(json []byte, path string, opts *Options) Result {
 	var result Result
 	if json != nil {
 		// unsafe cast to string
-		result = Get(*(*string)(unsafe.Pointer(&json)), path)
-		// safely get the string headers
-		rawhi := *(*stringHeader)(unsafe.Pointer(&result.Raw))
-		strhi := *(*stringHeader)(unsafe.Pointer(&result.Str))
-		// create byte slice headers
-		rawh := sliceHeader{data: rawhi.data, len: rawhi.len, cap: rawhi.len}
-		strh := sliceHeader{data: strhi.data, len: strhi.len, cap: rawhi.len}
-		if strh.data == nil {
-			// str is nil
-			if rawh.data == nil {
-				// raw is nil
-				result.Raw = ""
-			} else {
-				// raw has data, safely copy the slice header to a string
-				result.Raw = string(*(*[]byte)(unsafe.Pointer(&rawh)))
-			}
-			result.Str = ""
-		} else if rawh.data == nil {
+		result = copyResult(getWith(*(*string)(unsafe.Pointer(&json)), path, opts))
+	}
+	return result
+}
+
+// copyResult safely copies the strings of a result which was searched from an
+// unsafe casted string into uniquely allocated data.
+func copyResult(result Result) Result {
+	// safely get the string headers
+	rawhi := *(*stringHeader)(unsafe.Pointer(&result.Raw))
+	strhi := *(*stringHeader)(unsafe.Pointer(&result.Str))
+	// create byte slice headers
+	rawh := sliceHeader{data: rawhi.data, len: rawhi.len, cap: rawhi.len}
+	strh := sliceHeader{data: strhi.data, len: strhi.len, cap: rawhi.len}
+	if strh.data == nil {
+		// str is nil
+		if rawh.data == nil {
 			// raw is nil
 			result.Raw = ""
-			// str has data, safely copy the slice header to a string
-			result.Str = string(*(*[]byte)(unsafe.Pointer(&strh)))
-		} else if uintptr(strh.data) >= uintptr(rawh.data) &&
-			uintptr(strh.data)+uintptr(strh.len) <=
-				uintptr(rawh.data)+uintptr(rawh.len) {
-			// Str is a substring of Raw.
-			start := uintptr(strh.data) - uintptr(rawh.data)
-			// safely copy the raw slice header
-			result.Raw = string(*(*[]byte)(unsafe.Pointer(&rawh)))
-			// substring the raw
-			result.Str = result.Raw[start : start+uintptr(strh.len)]
 		} else {
-			// safely copy both the raw and str slice headers to strings
+			// raw has data, safely copy the slice header to a string
 			result.Raw = string(*(*[]byte)(unsafe.Pointer(&rawh)))
-			result.Str = string(*(*[]byte)(unsafe.Pointer(&strh)))
 		}
+		result.Str = ""
+	} else if rawh.data == nil {
+		// raw is nil
+		result.Raw = ""
+		// str has data, safely copy the slice header to a string
+		result.Str = string(*(*[]byte)(unsafe.Pointer(&strh)))
+	} else if uintptr(strh.data) >= uintptr(rawh.data) &&
+		uintptr(strh.data)+uintptr(strh.len) <=
+			uintptr(rawh.data)+uintptr(rawh.len) {
+		// Str is a substring of Raw.
+		start := uintptr(strh.data) - uintptr(rawh.data)
+		// safely copy the raw slice header
+		result.Raw = string(*(*[]byte)(unsafe.Pointer(&rawh)))
+		// substring the raw
+		result.Str = result.Raw[start : start+uintptr(strh.len)]
+	} else {
+		// safely copy both the raw and str slice headers to strings
+		result.Raw = string(*(*[]byte)(unsafe.Pointer(&rawh)))
+		result.Str = string(*(*[]byte)(unsafe.Pointer(&strh)))
 	}
 	return result
 }
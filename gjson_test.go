@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/cloudwego/gjson/internal/fast"
 	"github.com/tidwall/pretty"
 )
 
@@ -107,6 +109,62 @@ func TestEscapePath(t *testing.T) {
 	testEscapePath(t, json, "test.keyk\\*.key\\?", "val7")
 }
 
+func TestFastPathKeys(t *testing.T) {
+	json := `{
+		"user-id": 1, "$ref": "#/a", "中文": ["x", "y"], "x.y": {"z": true},
+		"a\u002db": "escaped", "a\\b": null, "0": {"1": "obj"},
+		"arr": [{"k*": 1}, [2, 3]], "dup": 1, "dup": 2
+	}`
+	paths := []string{
+		"user-id", "$ref", "中文.1", "中文.2", `x\.y.z`, "a-b", `a\\b`, "0.1",
+		"arr.0.k\\*", "arr.1.1", "arr.-1", "arr.01", "dup", "missing.key",
+		`\$ref`, `user\-id`, "arr.1.x",
+	}
+	opt := fast.FastPathEnable
+	defer func() { fast.FastPathEnable = opt }()
+	for _, path := range paths {
+		fast.FastPathEnable = false
+		exp := Get(json, path)
+		fast.FastPathEnable = true
+		if paths, _, _ := fast.FastPaths(path); paths == nil {
+			t.Fatalf("path %q is expected to be simple", path)
+		}
+		got := Get(json, path)
+		if !reflect.DeepEqual(exp, got) {
+			t.Fatalf("path %q: expected %#v, got %#v", path, exp, got)
+		}
+	}
+}
+
+func TestFastPathPrefix(t *testing.T) {
+	json := `{"a":{"b":1},"a":{"c":[{"d":2},{"d":3}]},"e":[{"f":{"g":4}}]}`
+	paths := append([]string{"a.c.#.d", "a.c.#(d>2).d", "e.0.f.g*", "e.0.f|g", "a.b|@this"},
+		samplePaths...)
+	opt := fast.FastPathEnable
+	defer func() { fast.FastPathEnable = opt }()
+	fast.FastPathEnable = true
+	if _, prefix, _ := fast.FastPaths("e.0.f.g*"); prefix == nil {
+		t.Fatal("expected a literal prefix")
+	}
+	for _, json := range append(sampleJSONs, json) {
+		for _, path := range paths {
+			fast.FastPathEnable = false
+			exp := Get(json, path)
+			fast.FastPathEnable = true
+			got := Get(json, path)
+			if !reflect.DeepEqual(exp, got) {
+				t.Fatalf("path %q: expected %#v, got %#v", path, exp, got)
+			}
+			if p, err := Compile(path); err == nil {
+				got = p.Get(json)
+				if !reflect.DeepEqual(exp, got) {
+					t.Fatalf("compiled path %q: expected %#v, got %#v", path, exp, got)
+				}
+			}
+		}
+	}
+}
+
 // this json block is poorly formed on purpose.
 var basicJSON = `  {"age":100, "name":{"here":"B\\\"R"},
 	"noop":{"what is a wren?":"a bird"},
@@ -656,8 +714,8 @@ func TestUnicode(t *testing.T) {
 }
 
 func TestUnescape(t *testing.T) {
-	unescape(string([]byte{'\\', '\\', 0}))
-	unescape(string([]byte{'\\', '/', '\\', 'b', '\\', 'f'}))
+	unescape(string([]byte{'\\', '\\', 0}), nil)
+	unescape(string([]byte{'\\', '/', '\\', 'b', '\\', 'f'}), nil)
 }
 func assert(t testing.TB, cond bool) {
 	if !cond {
@@ -0,0 +1,250 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package caching
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Policy is the eviction policy of a Bounded cache
+type Policy int
+
+const (
+	// CLOCK evicts an entry which is not read since the clock hand
+	// passed it last time, reads only need a read lock.
+	CLOCK Policy = iota
+	// LRU evicts the least recently used entry,
+	// reads need a write lock to reorder the entries.
+	LRU
+)
+
+// Stats is the statistics of a Bounded cache
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	// Size is the number of entries in the cache
+	Size int
+	// Capacity is the max number of entries in the cache
+	Capacity int
+}
+
+// shardThreshold is the min capacity to split a cache into shards,
+// since the capacity of a shard is rounded up.
+const (
+	shardThreshold = 1024
+	shardCount     = 16
+)
+
+// Bounded is a string-keyed cache safe for concurrent use,
+// which evicts entries by its policy once its capacity is reached.
+//
+// A Bounded cache with capacity <= 0 caches nothing, every get misses.
+type Bounded struct {
+	policy Policy
+	shards []shard
+}
+
+type shard struct {
+	mux  sync.RWMutex
+	keys map[string]int
+	ents []entry
+	cap  int
+	// hand of the clock
+	hand int
+	// most and least recently used entries for LRU
+	head, tail int
+
+	hits, misses, evictions uint64
+}
+
+type entry struct {
+	key string
+	val interface{}
+	// ref is set when the entry is read, and cleared by the clock hand
+	ref uint32
+	// links of the LRU list
+	prev, next int
+}
+
+// NewBounded creates a Bounded cache of the capacity and policy
+func NewBounded(capacity int, policy Policy) *Bounded {
+	n := 1
+	if capacity >= shardThreshold {
+		n = shardCount
+	}
+	self := &Bounded{policy: policy, shards: make([]shard, n)}
+	for i := range self.shards {
+		s := &self.shards[i]
+		s.cap = capacity / n
+		if i < capacity%n {
+			s.cap++
+		}
+		if s.cap > 0 {
+			s.keys = make(map[string]int)
+		}
+		s.head, s.tail = -1, -1
+	}
+	return self
+}
+
+func (self *Bounded) shard(key string) *shard {
+	if len(self.shards) == 1 {
+		return &self.shards[0]
+	}
+	// FNV-1a
+	h := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		h ^= uint32(key[i])
+		h *= 16777619
+	}
+	return &self.shards[h%uint32(len(self.shards))]
+}
+
+// GetByStr returns the val of the key, or nil if not found
+func (self *Bounded) GetByStr(key string) interface{} {
+	s := self.shard(key)
+	if s.cap <= 0 {
+		atomic.AddUint64(&s.misses, 1)
+		return nil
+	}
+	if self.policy == LRU {
+		s.mux.Lock()
+		i, ok := s.keys[key]
+		var val interface{}
+		if ok {
+			s.moveToFront(i)
+			val = s.ents[i].val
+		}
+		s.mux.Unlock()
+		if !ok {
+			atomic.AddUint64(&s.misses, 1)
+			return nil
+		}
+		atomic.AddUint64(&s.hits, 1)
+		return val
+	}
+
+	s.mux.RLock()
+	i, ok := s.keys[key]
+	var val interface{}
+	if ok {
+		e := &s.ents[i]
+		if atomic.LoadUint32(&e.ref) == 0 {
+			atomic.StoreUint32(&e.ref, 1)
+		}
+		val = e.val
+	}
+	s.mux.RUnlock()
+	if !ok {
+		atomic.AddUint64(&s.misses, 1)
+		return nil
+	}
+	atomic.AddUint64(&s.hits, 1)
+	return val
+}
+
+// SetByStr stores the key and val into the cache, evicting an entry if the
+// cache is full, and tells if the key already set
+func (self *Bounded) SetByStr(key string, val interface{}) (exist bool) {
+	s := self.shard(key)
+	if s.cap <= 0 {
+		return false
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if _, exist = s.keys[key]; exist {
+		return
+	}
+
+	i := len(s.ents)
+	if i < s.cap {
+		s.ents = append(s.ents, entry{key: key, val: val, prev: -1, next: -1})
+	} else {
+		if self.policy == LRU {
+			i = s.tail
+		} else {
+			i = s.victim()
+		}
+		s.evictions++
+		delete(s.keys, s.ents[i].key)
+		s.ents[i].key, s.ents[i].val, s.ents[i].ref = key, val, 0
+	}
+	s.keys[key] = i
+	if self.policy == LRU {
+		s.moveToFront(i)
+	}
+	return
+}
+
+// victim moves the clock hand to an entry not read since last time.
+// It must be called with the write lock.
+func (s *shard) victim() int {
+	for {
+		i := s.hand
+		s.hand = (s.hand + 1) % len(s.ents)
+		e := &s.ents[i]
+		if e.ref == 0 {
+			return i
+		}
+		e.ref = 0
+	}
+}
+
+// moveToFront moves the entry to the head of the LRU list.
+// It must be called with the write lock.
+func (s *shard) moveToFront(i int) {
+	if s.head == i {
+		return
+	}
+	e := &s.ents[i]
+	// unlink, if linked
+	if e.prev >= 0 {
+		s.ents[e.prev].next = e.next
+	}
+	if e.next >= 0 {
+		s.ents[e.next].prev = e.prev
+	}
+	if s.tail == i {
+		s.tail = e.prev
+	}
+	// link at head
+	e.prev, e.next = -1, s.head
+	if s.head >= 0 {
+		s.ents[s.head].prev = i
+	}
+	s.head = i
+	if s.tail < 0 {
+		s.tail = i
+	}
+}
+
+// Stats returns the statistics of the cache
+func (self *Bounded) Stats() (ret Stats) {
+	for i := range self.shards {
+		s := &self.shards[i]
+		ret.Hits += atomic.LoadUint64(&s.hits)
+		ret.Misses += atomic.LoadUint64(&s.misses)
+		s.mux.RLock()
+		ret.Evictions += s.evictions
+		ret.Size += len(s.ents)
+		s.mux.RUnlock()
+		ret.Capacity += s.cap
+	}
+	return
+}
@@ -0,0 +1,133 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package caching
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestBoundedLRU(t *testing.T) {
+	c := NewBounded(3, LRU)
+	for i := 0; i < 3; i++ {
+		if c.SetByStr(strconv.Itoa(i), i) {
+			t.Fatal("unexpected exist")
+		}
+	}
+	if !c.SetByStr("0", 0) {
+		t.Fatal("expected exist")
+	}
+	// 0 becomes the most recently used, so 1 is evicted
+	if c.GetByStr("0") != 0 {
+		t.Fatal("expected 0")
+	}
+	c.SetByStr("3", 3)
+	if c.GetByStr("1") != nil {
+		t.Fatal("expected 1 to be evicted")
+	}
+	for _, k := range []string{"0", "2", "3"} {
+		if c.GetByStr(k) == nil {
+			t.Fatalf("expected %s to be cached", k)
+		}
+	}
+	exp := Stats{Hits: 4, Misses: 1, Evictions: 1, Size: 3, Capacity: 3}
+	if st := c.Stats(); st != exp {
+		t.Fatalf("expected %+v, got %+v", exp, st)
+	}
+}
+
+func TestBoundedCLOCK(t *testing.T) {
+	c := NewBounded(3, CLOCK)
+	for i := 0; i < 3; i++ {
+		c.SetByStr(strconv.Itoa(i), i)
+	}
+	// 0 and 2 are referenced, so the hand evicts 1
+	c.GetByStr("0")
+	c.GetByStr("2")
+	c.SetByStr("3", 3)
+	if c.GetByStr("1") != nil {
+		t.Fatal("expected 1 to be evicted")
+	}
+	// the hand cleared the bit of 0 while passing it, and stopped at 2
+	c.SetByStr("4", 4)
+	if c.GetByStr("0") != nil {
+		t.Fatal("expected 0 to be evicted")
+	}
+	for _, k := range []string{"2", "3", "4"} {
+		if c.GetByStr(k) == nil {
+			t.Fatalf("expected %s to be cached", k)
+		}
+	}
+	if st := c.Stats(); st.Evictions != 2 || st.Size != 3 || st.Misses != 2 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+}
+
+func TestBoundedDisabled(t *testing.T) {
+	c := NewBounded(0, CLOCK)
+	if c.SetByStr("a", 1) || c.GetByStr("a") != nil {
+		t.Fatal("expected nothing cached")
+	}
+	if st := c.Stats(); st != (Stats{Misses: 1}) {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+}
+
+func TestBoundedConcurrent(t *testing.T) {
+	for _, policy := range []Policy{CLOCK, LRU} {
+		c := NewBounded(2000, policy)
+		wg := sync.WaitGroup{}
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < 10000; i++ {
+					k := strconv.Itoa((i * (g + 1)) % 5000)
+					if v := c.GetByStr(k); v != nil && v != k {
+						t.Errorf("key %s: unexpected val %v", k, v)
+						return
+					}
+					c.SetByStr(k, k)
+				}
+			}(g)
+		}
+		wg.Wait()
+		st := c.Stats()
+		if st.Size > st.Capacity || st.Capacity != 2000 || st.Hits+st.Misses != 80000 {
+			t.Fatalf("unexpected stats %+v", st)
+		}
+	}
+}
+
+func BenchmarkBounded(b *testing.B) {
+	for _, policy := range []Policy{CLOCK, LRU} {
+		c := NewBounded(1024, policy)
+		for i := 0; i < 1024; i++ {
+			c.SetByStr(strconv.Itoa(i), i)
+		}
+		b.Run(strconv.Itoa(int(policy)), func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					c.GetByStr(strconv.Itoa(i & 1023))
+					i++
+				}
+			})
+		})
+	}
+}
@@ -20,11 +20,15 @@ import (
 	"errors"
 	"os"
 	"strconv"
+	"sync/atomic"
 
 	"github.com/cloudwego/gjson/internal/caching"
 )
 
-var psCache caching.IncRCU
+// DefaultPathCacheSize is the default capacity of the cache of parsed paths
+const DefaultPathCacheSize = 10000
+
+var psCache atomic.Value // *caching.Bounded
 
 var (
 	FastPathEnable    = os.Getenv("GJSON_FAST_PATH") != ""
@@ -33,77 +37,148 @@ var (
 )
 
 func init() {
-	m := map[string]interface{}{}
-	psCache = caching.NewRCUStr(&m, 1000)
+	SetPathCache(DefaultPathCacheSize, caching.CLOCK)
+}
+
+// SetPathCache replaces the cache of parsed paths with an empty one,
+// which holds at most capacity paths and evicts them by the policy.
+// Parsed paths are not cached if capacity <= 0.
+func SetPathCache(capacity int, policy caching.Policy) {
+	psCache.Store(caching.NewBounded(capacity, policy))
+}
+
+// PathCacheStats returns the statistics of the cache of parsed paths,
+// since the last call of SetPathCache
+func PathCacheStats() caching.Stats {
+	return psCache.Load().(*caching.Bounded).Stats()
 }
 
 type parsedPaths struct {
 	simples []interface{}
+	// literal prefix and the rest of a path which is not simple
+	prefix []interface{}
+	rest   string
 }
 
-// check if it is a simple path and return parsed values.
-// Now only a path contains only alphabets, '_' and numbers can be regarded as simple
-// TODO: support more delicated simpliable cases
-func FastPaths(path string) []interface{} {
-	if !FastPathEnable {
-		return nil
-	}
+// FastPaths checks if it is a simple path and return parsed values, see ParsePaths.
+// Otherwise, it returns the parsed literal prefix and the rest of the path, see ParsePrefix.
+// The parsed values are cached, and callers are expected to check whether
+// the fast-path is enabled, such as FastPathEnable.
+func FastPaths(path string) (simples []interface{}, prefix []interface{}, rest string) {
+	cache := psCache.Load().(*caching.Bounded)
 	// read cache first
-	ps := psCache.GetByStr(path)
-	if ps != nil {
-		pp, ok := ps.(parsedPaths)
-		if !ok {
-			return nil
-		}
-		return pp.simples
+	if pp, _ := cache.GetByStr(path).(*parsedPaths); pp != nil {
+		return pp.simples, pp.prefix, pp.rest
+	}
+
+	pp := &parsedPaths{simples: ParsePaths(path)}
+	if pp.simples == nil {
+		pp.prefix, pp.rest = ParsePrefix(path)
 	}
+	// set to cache
+	_ = cache.SetByStr(path, pp)
+	return pp.simples, pp.prefix, pp.rest
+}
 
-	// parse paths
+// ParsePaths parses a simple path into keys and indexes that can be passed to Get.
+// A simple path is a chain of literal keys and non-negative indexes separated by '.',
+// where special chars of keys can be escaped by '\\'.
+// It returns nil if the path is not simple, such as containing wildcards,
+// queries or modifiers.
+func ParsePaths(path string) []interface{} {
 	var paths = []interface{}{}
 	var s = 0
 	var isNum = true
+	// unescaped key, only used when the key has escaped chars
+	var key []byte
+	var esc bool
 	for i := 0; i < len(path); i++ {
 		c := path[i]
 		if c == '.' {
-			if err := add_sub(&paths, path[s:i], isNum); err != nil {
-				paths = nil
-				goto set_paths
+			if err := add_sub(&paths, path[s:i], key, esc, isNum); err != nil {
+				return nil
 			}
 			if i+1 >= len(path) {
-				paths = nil
-				goto set_paths
+				return nil
 			}
 			s = i + 1
 			isNum = true
+			key, esc = key[:0], false
 			continue
 		}
-		is := isNameChar(c)
-		if !is {
-			paths = nil
-			goto set_paths
+		// escaped chars
+		if c == '\\' {
+			if i+1 >= len(path) {
+				return nil
+			}
+			if !esc {
+				key = append(key[:0], path[s:i]...)
+				esc = true
+			}
+			i++
+			key = append(key, path[i])
+			isNum = false
+			continue
+		}
+		if complexChars[c] {
+			return nil
+		}
+		if esc {
+			key = append(key, c)
 		}
 		isNum = isNum && isNumCahr(c)
-		// escaped chars
-		// if c == '\\' && i+1 < len(path) && escapableChars[path[i+1]] {
-		// 	i++
-		// 	continue
-		// }
-		// if complexChars[c] {
-		// 	paths = nil
-		// 	break
-		// }
 	}
-	if err := add_sub(&paths, path[s:], isNum); err != nil {
-		paths = nil
+	if err := add_sub(&paths, path[s:], key, esc, isNum); err != nil {
+		return nil
 	}
-
-	// set to cache
-set_paths:
-	_ = psCache.SetByStr(path, parsedPaths{paths})
 	return paths
 }
 
-func add_sub(paths *[]interface{}, sub string, isNum bool) error {
+// ParsePrefix splits a path which is not simple into its longest literal prefix
+// and the rest, such as `data.items` and `#(status="ok")#.id` for the path
+// `data.items.#(status="ok")#.id`. The prefix is parsed like ParsePaths.
+// It returns nil if the path has no literal prefix, or the rest starts with
+// a modifier, a static value or a multipath, which is piped to the prefix.
+func ParsePrefix(path string) (prefix []interface{}, rest string) {
+	// find the last '.' before the first complex char
+	dot := -1
+	i := 0
+	for ; i < len(path); i++ {
+		c := path[i]
+		if c == '\\' {
+			i++
+			continue
+		}
+		if c == '.' {
+			dot = i
+			continue
+		}
+		if complexChars[c] {
+			break
+		}
+	}
+	if i >= len(path) || dot <= 0 {
+		return nil, ""
+	}
+	rest = path[dot+1:]
+	switch rest[0] {
+	case '@', '!', '[', '{':
+		return nil, ""
+	}
+	if prefix = ParsePaths(path[:dot]); prefix == nil {
+		return nil, ""
+	}
+	return prefix, rest
+}
+
+func add_sub(paths *[]interface{}, sub string, key []byte, esc bool, isNum bool) error {
+	if esc {
+		*paths = append(*paths, string(key))
+		return nil
+	}
+	if sub == "" {
+		return errors.New("empty key")
+	}
 	if isNum {
 		index, err := strconv.ParseInt(sub, 10, 64)
 		if err != nil {
@@ -118,36 +193,26 @@ func add_sub(paths *[]interface{}, sub string, isNum bool) error {
 	return nil
 }
 
-func isNameChar(c byte) bool {
-	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || c == '_'
-}
-
 func isNumCahr(c byte) bool {
 	return ('0' <= c && c <= '9')
 }
 
-// var escapableChars = [256]bool{
-// 	'\\': true,
-// 	'.':  true,
-// 	'*':  true,
-// 	'?':  true,
-// }
-
-// var complexChars = [256]bool{
-// 	'*': true,
-// 	'"': true,
-// 	'%': true,
-// 	'?': true,
-// 	'#': true,
-// 	'|': true,
-// 	'@': true,
-// 	'[': true,
-// 	']': true,
-// 	'{': true,
-// 	'}': true,
-// 	'(': true,
-// 	')': true,
-// 	',': true,
-// 	'~': true,
-// 	'!': true,
-// }
+// complexChars are the chars which make a path not simple
+var complexChars = [256]bool{
+	'*': true,
+	'"': true,
+	'%': true,
+	'?': true,
+	'#': true,
+	'|': true,
+	'@': true,
+	'[': true,
+	']': true,
+	'{': true,
+	'}': true,
+	'(': true,
+	')': true,
+	',': true,
+	'~': true,
+	'!': true,
+}
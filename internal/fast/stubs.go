@@ -19,6 +19,7 @@ package fast
 import (
 	"errors"
 
+	"github.com/bytedance/sonic"
 	"github.com/bytedance/sonic/ast"
 )
 
@@ -39,22 +40,52 @@ func Skip(src string, i int) (end int, remian string) {
 	return e, src[s:e]
 }
 
-func String(json string, i int) (end int, str string, hasEsc bool, error error) {
-	v, r, hasEsc := decodeString(json, i, false, ValidStringEnable)
+// SkipValue skips the value after the spaces from src[i], and returns its start
+// and end, or an error if there is no value or it is malformed.
+func SkipValue(src string, i int) (start int, end int, err error) {
+	start, end, err = skipFast(src, i)
+	if err != nil || (src[start] != '-' && (src[start] < '0' || src[start] > '9')) {
+		return start, end, err
+	}
+	// a number is skipped up to the next delimiter, which may be after spaces
+	for j := start + 1; j < end; j++ {
+		switch c := src[j]; {
+		case c >= '0' && c <= '9', c == '.', c == 'e', c == 'E', c == '+', c == '-':
+		default:
+			return start, j, nil
+		}
+	}
+	return start, end, nil
+}
+
+// String decodes the json string at json[i], validating its UTF-8 if validate is set.
+func String(json string, i int, validate bool) (end int, str string, hasEsc bool, error error) {
+	v, r, hasEsc := decodeString(json, i, false, validate)
 	if r < 0 {
 		return i + 1, "", false, errJSON(json)
 	}
 	return r, v, hasEsc, nil
 }
 
+// Unmarshal decodes the json into v by the decoder of sonic, which is
+// compatible with encoding/json.
+func Unmarshal(json string, v interface{}) error {
+	return sonic.ConfigStd.UnmarshalFromString(json, v)
+}
+
 func Unquote(str string) (string, error) {
 	out, err := unquote(str, false)
-	if err != nil {
+	if err != nil && err != errUnquoteOK {
 		return "", err
 	}
 	return out, nil
 }
 
+// errUnquoteOK is the zero sonic types.ParsingError, which unquote returns
+// as a non-nil error on success. The type is internal to sonic, so it is
+// taken from the exported field of ast.SyntaxError.
+var errUnquoteOK error = ast.SyntaxError{}.Code
+
 func JSONType(sonic int) int {
 	switch sonic {
 	case ast.V_NULL:
@@ -17,6 +17,7 @@
 package fast
 
 import (
+	"reflect"
 	"strconv"
 	"testing"
 )
@@ -79,7 +80,7 @@ func TestString(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, got, hasEsc, err := String(tt.args.json, 0)
+			_, got, hasEsc, err := String(tt.args.json, 0, false)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("String() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -98,7 +99,7 @@ func BenchmarkString(b *testing.B) {
 	var js = `"abc中文"`
 	b.Run("Wrapper", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			_, _, _, _ = String(js, 0)
+			_, _, _, _ = String(js, 0, false)
 		}
 	})
 	b.Run("std", func(b *testing.B) {
@@ -107,3 +108,87 @@ func BenchmarkString(b *testing.B) {
 		}
 	})
 }
+
+func TestParsePaths(t *testing.T) {
+	tests := []struct {
+		path string
+		want []interface{}
+	}{
+		{"a", []interface{}{"a"}},
+		{"a.0.b_1", []interface{}{"a", 0, "b_1"}},
+		{"user-id.$ref", []interface{}{"user-id", "$ref"}},
+		{"中文.1", []interface{}{"中文", 1}},
+		{`x\.y.z`, []interface{}{"x.y", "z"}},
+		{`a\\b`, []interface{}{`a\b`}},
+		{`\1.2`, []interface{}{"1", 2}},
+		{`k\*.k\?`, []interface{}{"k*", "k?"}},
+		{"what is a wren", []interface{}{"what is a wren"}},
+		{"", nil},
+		{"a.", nil},
+		{".a", nil},
+		{"a..b", nil},
+		{`a\`, nil},
+		{"a.-1", []interface{}{"a", "-1"}},
+		{"a.99999999999999999999", nil},
+		{"a*", nil},
+		{"a.b?", nil},
+		{"a.#", nil},
+		{"a.#(b=1)", nil},
+		{"a|b", nil},
+		{"a.@reverse", nil},
+		{"!true", nil},
+		{"[a,b]", nil},
+		{"{a,b}", nil},
+	}
+	for _, tt := range tests {
+		if got := ParsePaths(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParsePaths(%q) = %#v, want %#v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParsePrefix(t *testing.T) {
+	tests := []struct {
+		path   string
+		prefix []interface{}
+		rest   string
+	}{
+		{`data.items.#(status="ok")#.id`, []interface{}{"data", "items"}, `#(status="ok")#.id`},
+		{"a.0.b*.c", []interface{}{"a", 0}, "b*.c"},
+		{`a\.b.#`, []interface{}{"a.b"}, "#"},
+		{"a.b|c", []interface{}{"a"}, "b|c"},
+		{"a|b.c", nil, ""},
+		{"a.b.c", nil, ""},
+		{"#.a", nil, ""},
+		{"a.@reverse", nil, ""},
+		{"a.[b,c]", nil, ""},
+		{"a.!true", nil, ""},
+		{"a..#", nil, ""},
+		{`a\.#.b`, nil, ""},
+	}
+	for _, tt := range tests {
+		prefix, rest := ParsePrefix(tt.path)
+		if !reflect.DeepEqual(prefix, tt.prefix) || rest != tt.rest {
+			t.Errorf("ParsePrefix(%q) = %#v, %q, want %#v, %q", tt.path, prefix, rest, tt.prefix, tt.rest)
+		}
+	}
+}
+
+func TestUnquote(t *testing.T) {
+	for _, tt := range []struct {
+		in, want string
+	}{
+		{`abc`, "abc"},
+		{`a-b`, "a-b"},
+		{`a\"b\\c`, `a"b\c`},
+		{`☺\n`, "☺\n"},
+	} {
+		got, err := Unquote(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("Unquote(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := Unquote(`\x`); err == nil {
+		t.Error("expected an error")
+	}
+}
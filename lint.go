@@ -0,0 +1,497 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ValidatePath returns nil if the path is well-formed, otherwise a
+// *PathSyntaxError describing the first error found.
+//
+// The whole path syntax is checked, like Compile does, thus the paths which
+// are accepted by Get but never match are reported too, such as unbalanced
+// brackets, invalid modifier arguments and static values, invalid query
+// operators and values, and data after a query or a multipath.
+//
+// The modifiers are checked against the ones added by AddModifier, which
+// should be added before. Like Get does, a @name component which is not a
+// modifier is an object key, such as @context in "@context.@vocab".
+//
+//	if err := gjson.ValidatePath(`friends.#(last=="Murphy"`); err != nil {
+//		// gjson: unclosed query at offset 8 of path "friends.#(last==\"Murphy\""
+//	}
+func ValidatePath(path string) error {
+	return validatePath(path, nil)
+}
+
+// ValidPath returns true if the path is well-formed, see ValidatePath.
+func ValidPath(path string) bool {
+	return validatePath(path, nil) == nil
+}
+
+func validatePath(path string, opts *Options) error {
+	l := pathLinter{src: path, opts: opts}
+	return l.path(0, len(path))
+}
+
+// pathLinter checks the syntax of a path. Every method checks a part of src
+// ending before end, and returns the position after the part.
+type pathLinter struct {
+	src  string
+	opts *Options
+}
+
+func (l *pathLinter) errorAt(i int, msg string) error {
+	return &PathSyntaxError{Path: l.src, Offset: i, Msg: msg}
+}
+
+func (l *pathLinter) unexpectedAt(i int, after string) error {
+	return l.errorAt(i, "unexpected "+quoteChar(stringBytes(l.src[i:]))+" after "+after)
+}
+
+// path checks src[i:end] as a whole path, like getWith parses it.
+func (l *pathLinter) path(i, end int) (err error) {
+	if i == end {
+		return l.errorAt(i, "empty path")
+	}
+	switch c := l.src[i]; {
+	case c == '@' && l.opts.modifiers() && modifiers[l.modifierName(i, end)] != nil:
+		// like getWith, a path which is not a modifier is a key
+		if i, err = l.modifier(i, end); err != nil {
+			return err
+		}
+		return l.next(i, end, "modifier")
+	case c == '!':
+		if i, err = l.static(i, end); err != nil {
+			return err
+		}
+		return l.next(i, end, "static value")
+	case c == '[' || c == '{':
+		if i, err = l.multipath(i, end); err != nil {
+			return err
+		}
+		return l.next(i, end, "multipath")
+	case c == '.' && end-i >= 2 && l.src[i+1] == '.':
+		// JSON Lines
+		if i += 2; i == end {
+			return l.errorAt(i, "unexpected end of path")
+		}
+	}
+	return l.components(i, end)
+}
+
+// next checks the path following a modifier, a static value or a multipath.
+func (l *pathLinter) next(i, end int, after string) error {
+	if i == end {
+		return nil
+	}
+	if c := l.src[i]; c != '.' && c != '|' {
+		return l.unexpectedAt(i, after)
+	}
+	if i+1 == end {
+		return l.errorAt(i+1, "unexpected end of path")
+	}
+	return l.path(i+1, end)
+}
+
+// components checks the keys and the queries of a plain path.
+func (l *pathLinter) components(i, end int) (err error) {
+	for {
+		if l.src[i] == '#' && i+1 < end && (l.src[i+1] == '(' || l.src[i+1] == '[') {
+			if i, err = l.query(i, end); err != nil {
+				return err
+			}
+			if i < end && l.src[i] == '#' {
+				i++
+			}
+			if i < end && l.src[i] != '.' && l.src[i] != '|' {
+				return l.unexpectedAt(i, "query")
+			}
+		} else {
+			for ; i < end && l.src[i] != '.' && l.src[i] != '|'; i++ {
+				if l.src[i] == '\\' {
+					if i++; i == end {
+						return l.errorAt(i, "unexpected end of path")
+					}
+				}
+			}
+		}
+		if i == end {
+			return nil
+		}
+		if i+1 == end {
+			return l.errorAt(i+1, "unexpected end of path")
+		}
+		if l.src[i] == '|' {
+			return l.path(i+1, end)
+		}
+		switch l.src[i+1] {
+		case '@', '[', '{':
+			if l.opts.modifiers() {
+				return l.path(i+1, end)
+			}
+		}
+		i++
+	}
+}
+
+// modifierName returns the name of the modifier at src[i], which ends at its
+// arguments or at the next component.
+func (l *pathLinter) modifierName(i, end int) string {
+	j := i + 1
+	for ; j < end && l.src[j] != ':' && l.src[j] != '.' && l.src[j] != '|'; j++ {
+	}
+	return l.src[i+1 : j]
+}
+
+// modifier checks the modifier at src[i], with its arguments.
+func (l *pathLinter) modifier(i, end int) (int, error) {
+	j := i + 1 + len(l.modifierName(i, end))
+	if j == end || l.src[j] != ':' {
+		return j, nil
+	}
+	if j+1 == end {
+		// no arguments after the colon
+		return end, nil
+	}
+	j++
+	switch l.src[j] {
+	case '{', '[', '"':
+		// json argument
+		arg := squash(l.src[j:end])
+		if !Valid(arg) {
+			return j, l.errorAt(j, "invalid modifier argument")
+		}
+		return j + len(arg), nil
+	}
+	for ; j < end && l.src[j] != '|'; j++ {
+		switch l.src[j] {
+		case '{', '[', '"', '(':
+			j += len(squash(l.src[j:end])) - 1
+		}
+	}
+	return j, nil
+}
+
+// static checks the static value at src[i].
+func (l *pathLinter) static(i, end int) (int, error) {
+	rest, value, ok := parseStatic(l.src[i:end])
+	if !ok {
+		return i, l.errorAt(i, "invalid static value")
+	}
+	switch value[0] {
+	case '{', '[', '"':
+		if !Valid(value) {
+			return i, l.errorAt(i+1, "invalid static value")
+		}
+	}
+	return end - len(rest), nil
+}
+
+// multipath checks the multipath at src[i], with its selectors.
+func (l *pathLinter) multipath(i, end int) (int, error) {
+	stack := []byte{l.src[i]}
+	start, colon, modifier := i+1, -1, false
+	for j := i + 1; j < end; j++ {
+		switch c := l.src[j]; c {
+		case '\\':
+			j++
+		case '@':
+			if !modifier && (l.src[j-1] == '.' || l.src[j-1] == '|') {
+				modifier = true
+			}
+		case ':':
+			if !modifier && colon < 0 && len(stack) == 1 {
+				colon = j
+			}
+		case ',':
+			if len(stack) == 1 {
+				if err := l.selector(start, colon, j); err != nil {
+					return j, err
+				}
+				start, colon, modifier = j+1, -1, false
+			}
+		case '"':
+			for j++; j < end && l.src[j] != '"'; j++ {
+				if l.src[j] == '\\' {
+					j++
+				}
+			}
+		case '[', '(', '{':
+			stack = append(stack, c)
+		case ']', ')', '}':
+			if !closes(stack[len(stack)-1], c) {
+				return j, l.errorAt(j, "unexpected "+strconv.QuoteRune(rune(c))+" in multipath")
+			}
+			if stack = stack[:len(stack)-1]; len(stack) > 0 {
+				continue
+			}
+			if start == j && start == i+1 {
+				// an empty multipath
+				return j + 1, nil
+			}
+			return j + 1, l.selector(start, colon, j)
+		}
+	}
+	return i, l.errorAt(i, "unclosed multipath")
+}
+
+// selector checks the selector src[start:end] of a multipath, whose name ends
+// at colon if it is not negative.
+func (l *pathLinter) selector(start, colon, end int) error {
+	if colon >= 0 {
+		if name := l.src[start:colon]; len(name) > 0 && name[0] == '"' && !Valid(name) {
+			return l.errorAt(start, "invalid selector name")
+		}
+		start = colon + 1
+	}
+	if start == end {
+		return l.errorAt(start, "empty selector")
+	}
+	return l.path(start, end)
+}
+
+// query checks the query at src[i], which is a '#' followed by a '(' or '['.
+func (l *pathLinter) query(i, end int) (int, error) {
+	stack := []byte{l.src[i+1]}
+	j := i + 2
+	for ; j < end && len(stack) > 0; j++ {
+		c := l.src[j]
+		switch c {
+		case '\\':
+			j++
+		case '"':
+			q := j
+			for j++; j < end && l.src[j] != '"'; j++ {
+				if l.src[j] == '\\' {
+					j++
+				}
+			}
+			if j >= end {
+				return q, l.errorAt(q, "unterminated string")
+			}
+		case '[', '(':
+			stack = append(stack, c)
+		case ']', ')':
+			if !closes(stack[len(stack)-1], c) {
+				return j, l.errorAt(j, "unexpected "+strconv.QuoteRune(rune(c))+" in query")
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if len(stack) > 0 {
+		return i, l.errorAt(i, "unclosed query")
+	}
+	// the closing bracket
+	closing := j - 1
+	if isCompoundQuery(l.src[i+2 : closing]) {
+		return j, l.compound(i+2, closing)
+	}
+	return j, l.comparison(i+2, closing)
+}
+
+// compound checks the compound query in src[i:end], and its comparisons.
+func (l *pathLinter) compound(i, end int) error {
+	p := queryParser{src: l.src[i:end], opts: l.opts}
+	x := p.parse()
+	if x == nil {
+		return l.errorAt(i+p.pos, p.msg)
+	}
+	return l.comparisons(x)
+}
+
+func (l *pathLinter) comparisons(x *queryExpr) error {
+	if x.op == 0 {
+		i := offsetOf(l.src, x.raw)
+		return l.comparison(i, i+len(x.raw))
+	}
+	if err := l.comparisons(x.left); err != nil {
+		return err
+	}
+	if x.right != nil {
+		return l.comparisons(x.right)
+	}
+	return nil
+}
+
+// comparison checks the path, operator and value of the comparison in
+// src[i:end].
+func (l *pathLinter) comparison(i, end int) error {
+	if ws, we := findWordOp(l.src[i:end]); ws >= 0 {
+		return l.wordOp(i, i+ws, i+we, end)
+	}
+	op := -1
+	depth := 0
+	for j := i; j < end; j++ {
+		c := l.src[j]
+		if depth == 0 && strings.IndexByte("!=<>%", c) >= 0 {
+			op = j
+			break
+		}
+		switch c {
+		case '\\':
+			j++
+		case '"':
+			for j++; j < end && l.src[j] != '"'; j++ {
+				if l.src[j] == '\\' {
+					j++
+				}
+			}
+		case '[', '(':
+			depth++
+		case ']', ')':
+			depth--
+		}
+	}
+	pend := end
+	if op >= 0 {
+		pend = op
+	} else if k := findTypeOp(l.src[i:end]); k >= 0 {
+		if ps, pe := l.trim(i, i+k); ps < pe {
+			return l.path(ps, pe)
+		}
+		return nil
+	}
+	ps, pe := l.trim(i, pend)
+	if op < 0 && ps == pe {
+		return l.errorAt(ps, "empty query")
+	}
+	if ps < pe {
+		if err := l.path(ps, pe); err != nil {
+			return err
+		}
+	}
+	if op >= 0 {
+		return l.queryValue(op, end)
+	}
+	return nil
+}
+
+// queryValue checks the operator at src[op] and the value following it,
+// which ends before the closing bracket of the query.
+func (l *pathLinter) queryValue(op, end int) error {
+	n := 1
+	regex := false
+	switch l.src[op] {
+	case '!':
+		n = 2
+		if op+1 < end && l.src[op+1] == '~' {
+			regex = true
+		} else if op+1 == end || (l.src[op+1] != '=' && l.src[op+1] != '%') {
+			return l.errorAt(op, "invalid operator \"!\"")
+		}
+	case '=':
+		if op+1 < end && l.src[op+1] == '~' && isRegexpValue(l.src[op+2:end]) {
+			n, regex = 2, true
+		} else if op+1 < end && l.src[op+1] == '=' {
+			n = 2
+		}
+	case '<', '>':
+		if op+1 < end && l.src[op+1] == '=' {
+			n = 2
+		}
+	}
+	if v := op + n; l.src[v-1] != '%' && v < end && strings.IndexByte("!=<>", l.src[v]) >= 0 {
+		return l.errorAt(op, "invalid operator "+strconv.Quote(l.src[op:v+1]))
+	}
+	vs, ve := l.trim(op+n, end)
+	if vs == ve {
+		return l.errorAt(vs, "missing value after operator")
+	}
+	if regex {
+		if !isRegexpValue(l.src[vs:ve]) {
+			return l.errorAt(vs, "regular expression must be a string")
+		}
+		if _, err := regexp.Compile(queryValue(l.src[vs:ve], true, l.opts)); err != nil {
+			return l.errorAt(vs, "invalid regular expression")
+		}
+	}
+	return l.value(vs, ve)
+}
+
+// wordOp checks the comparison in src[i:end] by the word operator in
+// src[ws:we], such as "in" or "contains".
+func (l *pathLinter) wordOp(i, ws, we, end int) error {
+	if ps, pe := l.trim(i, ws); ps < pe {
+		if err := l.path(ps, pe); err != nil {
+			return err
+		}
+	}
+	vs, ve := l.trim(we, end)
+	if vs == ve {
+		return l.errorAt(vs, "missing value after operator")
+	}
+	if op := l.src[ws:we]; (op == "in" || op == "!in") && parseQueryRef(l.src[vs:ve]) == nil {
+		if l.src[vs] != '[' || !Valid(l.src[vs:ve]) {
+			return l.errorAt(vs, "invalid list after "+strconv.Quote(op))
+		}
+		return nil
+	}
+	return l.value(vs, ve)
+}
+
+// value checks the value of a comparison in src[vs:ve].
+func (l *pathLinter) value(vs, ve int) error {
+	switch l.src[vs] {
+	case '"':
+		j := vs + 1
+		for ; l.src[j] != '"'; j++ {
+			if l.src[j] == '\\' {
+				j++
+			}
+		}
+		if j+1 < ve {
+			return l.unexpectedAt(j+1, "string")
+		}
+	case '@', '$':
+		if parseQueryRef(l.src[vs:ve]) != nil {
+			return l.path(vs+2, ve)
+		}
+	case '~':
+		switch l.src[vs+1 : ve] {
+		case "*", "null", "true", "false":
+		default:
+			return l.errorAt(vs, "invalid value "+strconv.Quote(l.src[vs:ve]))
+		}
+	}
+	return nil
+}
+
+// trim returns the span of src[i:end] without the spaces around it.
+func (l *pathLinter) trim(i, end int) (int, int) {
+	for i < end && l.src[i] <= ' ' {
+		i++
+	}
+	for end > i && l.src[end-1] <= ' ' {
+		end--
+	}
+	return i, end
+}
+
+// closes tells if the closing bracket c matches the opening bracket open.
+func closes(open, c byte) bool {
+	switch open {
+	case '(':
+		return c == ')'
+	case '[':
+		return c == ']'
+	}
+	return c == '}'
+}
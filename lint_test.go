@@ -0,0 +1,113 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidatePath(t *testing.T) {
+	for _, path := range []string{
+		"name.last", "a..b", `fav\.movie`, "children|#", "children.#", "..0", "..#.name",
+		`friends.#(last=="Murphy").first`, `friends.#(last=="Murphy")#.first`, `friends.#[age>=40]`,
+		`friends.#(nets.#(=="fb"))#.first`, `friends.#(first%"D*")`, `friends.#(first!%"D*")`,
+		`friends.#(nets.#(=="fb")).last`, `friends.#(active==~true)`, "children|@reverse|0",
+		"children.@reverse", `@join:{"preserve":true}`, "@pretty:{\"indent\":\"\\t\"}", "[]", "{}",
+		`{name.first,"age":age,nets:friends.0.nets}`, "[name.first,age]|@ugly", `!{"a":1}.a`, "!true",
+		`name.@dig:last`, `friends.#.{first,age}`, `friends.#(age>40 && (last=="Murphy" || !nets))#`,
+		`friends.#(!(age<45))`, `orders.#(budget<$.limits.max && price<=@.budget)`, `friends.#(email=~"^.*@corp\\.com$")#`,
+		`friends.#(first!~"^D")`, `friends.#(active=~true)`,
+		`friends.#(last in ["Murphy","Craig"])`, `friends.#(nets !contains "fb")`, `friends.#(first startsWith "D")`,
+		`friends.#(age:number && !(nets:array))`, `friends.#(:object)`, `friends.#(nets.#(=="fb") && first!="Dale")`,
+		// the paths which are not modifiers are keys
+		"@context.@vocab", "children|@reverse:", "children|@nope", `friends.#(nets.@nope:array)`, `orders.#(budget<@.@nope)`, "@",
+	} {
+		if err := ValidatePath(path); err != nil {
+			t.Fatalf("%q: %v", path, err)
+		}
+		assert(t, ValidPath(path))
+		_, err := Compile(path)
+		assert(t, err == nil)
+	}
+
+	for _, c := range []struct {
+		path   string
+		offset int
+		msg    string
+	}{
+		{"", 0, "empty path"},
+		{"name.", 5, "unexpected end of path"},
+		{"name|", 5, "unexpected end of path"},
+		{`name\`, 5, "unexpected end of path"},
+		{`friends.#(last=="Murphy"`, 8, "unclosed query"},
+		{`friends.#(last=="Murphy"]`, 24, "unexpected ']' in query"},
+		{`friends.#(last=="Murphy)`, 16, "unterminated string"},
+		{`friends.#(last==)`, 16, "missing value after operator"},
+		{`friends.#(age=>40)`, 13, `invalid operator "=>"`},
+		{`friends.#(age!40)`, 13, `invalid operator "!"`},
+		{`friends.#(last=="Murphy"x)`, 24, "unexpected 'x' after string"},
+		{`friends.#(active==~yes)`, 18, `invalid value "~yes"`},
+		{`friends.#()`, 10, "empty query"},
+		{`friends.#(age>40)x`, 17, "unexpected 'x' after query"},
+		{`friends.#(age>40)#junk`, 18, "unexpected 'j' after query"},
+		{`friends.#(a==1) junk`, 15, "unexpected ' ' after query"},
+		{"[a,b]x", 5, "unexpected 'x' after multipath"},
+		{"!tru", 0, "invalid static value"},
+		{"@reverse:{", 9, "invalid modifier argument"},
+		{`friends.#(nets.#(=="fb")`, 8, "unclosed query"},
+		{`@join:{"preserve"}`, 6, "invalid modifier argument"},
+		{"@reverse.", 9, "unexpected end of path"},
+		{"!nope", 0, "invalid static value"},
+		{`!{"a":1}x`, 8, "unexpected 'x' after static value"},
+		{"[name,age", 0, "unclosed multipath"},
+		{"[name,)", 6, "unexpected ')' in multipath"},
+		{"[name,]", 6, "empty selector"},
+		{`{"name:name}`, 0, "unclosed multipath"},
+		{"{name,age}x", 10, "unexpected 'x' after multipath"},
+		{"{name,friends.#(age>)}", 20, "missing value after operator"},
+		{`friends.#(age>40 &&)`, 19, "missing operand in query"},
+		{`friends.#(|| age>40)`, 10, "missing operand in query"},
+		{`friends.#((age>40) x)`, 19, "unexpected 'x' in query"},
+		{`friends.#(age>40 || last=>"M")`, 24, `invalid operator "=>"`},
+		{`friends.#(last in "Murphy")`, 18, `invalid list after "in"`},
+		{`friends.#(last in [Murphy])`, 18, `invalid list after "in"`},
+		{`friends.#(a in [1,2)`, 19, "unexpected ')' in query"},
+		{`friends.#(last contains )`, 24, "missing value after operator"},
+		{`friends.#(age>40 && last in)#`, 27, "missing value after operator"},
+		{`friends.#(first endsWith "e"x)`, 28, "unexpected 'x' after string"},
+		{`friends.#(first=~"(")`, 17, "invalid regular expression"},
+		{`friends.#(first!~true)`, 17, "regular expression must be a string"},
+		{`orders.#(budget<$.limits.)`, 25, "unexpected end of path"},
+		{`friends.#(!(active==~yes))`, 20, `invalid value "~yes"`},
+	} {
+		err := ValidatePath(c.path)
+		var perr *PathSyntaxError
+		if !errors.As(err, &perr) || perr.Offset != c.offset || perr.Msg != c.msg {
+			t.Fatalf("%q: %v", c.path, err)
+		}
+		assert(t, perr.Path == c.path && !ValidPath(c.path))
+		// Compile reports the same error
+		if _, cerr := Compile(c.path); cerr == nil || cerr.Error() != err.Error() {
+			t.Fatalf("compiled %q: expected %v, got %v", c.path, err, cerr)
+		}
+	}
+
+	// modifiers are object keys if disabled
+	assert(t, NewParser(Options{DisableModifiers: true}).ValidatePath("children.@reverse:{") == nil)
+	assert(t, NewParser(Options{}).ValidatePath("children.@reverse:{") != nil)
+}
@@ -0,0 +1,367 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"strconv"
+	"unsafe"
+)
+
+// GetMany searches json for the multiple paths.
+// The return value is a Result array where the number of items
+// will be equal to the number of input paths.
+//
+// Simple paths, which consist of object keys and array indexes only, are
+// searched together in a single pass over json. Other paths, such as the ones
+// with wildcards, queries or modifiers, are searched one by one with Get.
+func GetMany(json string, path ...string) []Result {
+	return getMany(json, path, nil)
+}
+
+func getMany(json string, path []string, opts *Options) []Result {
+	res := make([]Result, len(path))
+	if len(path) < 2 {
+		for i, path := range path {
+			res[i] = getWith(json, path, opts)
+		}
+		return res
+	}
+	t := newPathTrie(len(path), opts)
+	ends := make([]int, len(path))
+	for i, path := range path {
+		if ends[i] = t.add(path); ends[i] < 0 {
+			res[i] = getWith(json, path, opts)
+		}
+	}
+	if t.nodes[0].pending > 0 {
+		for i := 0; i < len(json); i++ {
+			if json[i] == '{' {
+				t.object(json, i+1, 0)
+				break
+			}
+			if json[i] == '[' {
+				t.array(json, i+1, 0)
+				break
+			}
+		}
+	}
+	for i, n := range ends {
+		if n >= 0 {
+			res[i] = t.nodes[n].value
+		}
+	}
+	return res
+}
+
+// GetManyBytes searches json for the multiple paths.
+// The return value is a Result array where the number of items
+// will be equal to the number of input paths.
+func GetManyBytes(json []byte, path ...string) []Result {
+	return getManyBytes(json, path, nil)
+}
+
+func getManyBytes(json []byte, path []string, opts *Options) []Result {
+	if json == nil {
+		return make([]Result, len(path))
+	}
+	// unsafe cast to string
+	res := getMany(*(*string)(unsafe.Pointer(&json)), path, opts)
+	for i := range res {
+		res[i] = copyResult(res[i])
+	}
+	return res
+}
+
+// pathTrie is a tree of simple paths, where each node is a path component.
+// The paths are searched in one pass over a json, following the same rules
+// as parseObject and parseArray, so that the results are the same as
+// searching the paths one by one.
+type pathTrie struct {
+	// nodes holds the root at 0, and nodes link each other by positions
+	nodes []trieNode
+	opts  *Options
+}
+
+func newPathTrie(paths int, opts *Options) pathTrie {
+	t := pathTrie{nodes: make([]trieNode, 1, 4*paths), opts: opts}
+	t.nodes[0] = trieNode{idx: -1, parent: -1, child: -1, next: -1}
+	return t
+}
+
+type trieNode struct {
+	// key is the component matched with object keys
+	key string
+	// idx is the component matched with array indexes, or -1
+	idx                 int
+	parent, child, next int
+	// leaf is set if a path ends at this node
+	leaf  bool
+	found bool
+	// pending is the number of leaf nodes not found yet in the subtree
+	pending int
+	value   Result
+}
+
+// isManyPath reports whether the path can be searched in the path trie,
+// which means it is parsed by parseObjectPath and parseArrayPath as plain
+// components separated by dots.
+func isManyPath(path string) bool {
+	if len(path) == 0 || path[0] == '.' {
+		return false
+	}
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '*', '?', '#', '|', '@', '[', '{', '!':
+			return false
+		}
+	}
+	return true
+}
+
+// splitManyPath returns the first component of a simple path, as an object
+// key and an array index, and the rest of the path.
+func splitManyPath(path string, opts *Options) (key string, idx int, rest string, more, ok bool) {
+	rp := parseObjectPath(path, opts)
+	part := path
+	if rp.more {
+		part = path[:len(path)-len(rp.path)-1]
+	}
+	idx = -1
+	if v, isNum := parseUint(part); isNum {
+		// a component like "01" matches the same array element as "1"
+		// but another object key, which a node cannot represent
+		if idx = int(v); strconv.Itoa(idx) != part {
+			return "", 0, "", false, false
+		}
+	}
+	return rp.part, idx, rp.path, rp.more, true
+}
+
+// add adds a path to the trie and returns the node where it ends,
+// or -1 if the path is not simple. The nodes added for a path which
+// is not simple are never pending, thus never matched.
+func (t *pathTrie) add(path string) int {
+	if !isManyPath(path) {
+		return -1
+	}
+	n := 0
+	for {
+		key, idx, rest, more, ok := splitManyPath(path, t.opts)
+		if !ok {
+			return -1
+		}
+		n = t.child(n, key, idx)
+		if !more {
+			break
+		}
+		path = rest
+	}
+	t.leaf(n)
+	return n
+}
+
+// leaf marks a path ending at n.
+func (t *pathTrie) leaf(n int) {
+	if !t.nodes[n].leaf {
+		t.nodes[n].leaf = true
+		for p := n; p >= 0; p = t.nodes[p].parent {
+			t.nodes[p].pending++
+		}
+	}
+}
+
+func (t *pathTrie) child(n int, key string, idx int) int {
+	for c := t.nodes[n].child; c >= 0; c = t.nodes[c].next {
+		if t.nodes[c].key == key {
+			return c
+		}
+	}
+	c := len(t.nodes)
+	t.nodes = append(t.nodes, trieNode{key: key, idx: idx, parent: n, child: -1, next: t.nodes[n].child})
+	t.nodes[n].child = c
+	return c
+}
+
+// matchKey returns the pending child matching an object key, or -1.
+func (t *pathTrie) matchKey(n int, key string) int {
+	for c := t.nodes[n].child; c >= 0; c = t.nodes[c].next {
+		if t.nodes[c].pending > 0 && t.nodes[c].key == key {
+			return c
+		}
+	}
+	return -1
+}
+
+// matchIndex returns the pending child matching an array index, or -1.
+func (t *pathTrie) matchIndex(n int, idx int) int {
+	for c := t.nodes[n].child; c >= 0; c = t.nodes[c].next {
+		if t.nodes[c].pending > 0 && t.nodes[c].idx == idx {
+			return c
+		}
+	}
+	return -1
+}
+
+// resolve sets the result of the paths ending at n.
+// It reports true if all the paths of the trie are found.
+func (t *pathTrie) resolve(n int, value Result) bool {
+	t.nodes[n].found = true
+	t.nodes[n].value = value
+	for p := n; p >= 0; p = t.nodes[p].parent {
+		t.nodes[p].pending--
+	}
+	return t.nodes[0].pending == 0
+}
+
+// object walks the object members from json[i], which follows a '{'.
+// It returns the position after the object, and reports true if all the
+// paths of the trie are found.
+func (t *pathTrie) object(json string, i int, n int) (int, bool) {
+	for i < len(json) {
+		for ; i < len(json); i++ {
+			if json[i] == '"' {
+				break
+			}
+			if json[i] == '}' {
+				return i + 1, false
+			}
+		}
+		if i >= len(json) {
+			break
+		}
+		var key string
+		var kesc, ok, done bool
+		i, key, _, kesc, ok = parseString(json, i, t.opts)
+		if !ok {
+			return i, false
+		}
+		key = key[1 : len(key)-1]
+		if kesc {
+			key = unescape(key, t.opts)
+		}
+		if i, done = t.value(json, i, t.matchKey(n, key), false); done {
+			return i, true
+		}
+	}
+	return i, false
+}
+
+// array walks the array elements from json[i], which follows a '['.
+func (t *pathTrie) array(json string, i int, n int) (int, bool) {
+	for h := 0; i < len(json); h++ {
+		for ; i < len(json); i++ {
+			if json[i] == ']' {
+				return i + 1, false
+			}
+			if json[i] > ' ' && json[i] != ',' {
+				break
+			}
+		}
+		if i >= len(json) {
+			break
+		}
+		var done bool
+		if i, done = t.value(json, i, t.matchIndex(n, h), true); done {
+			return i, true
+		}
+	}
+	return i, false
+}
+
+// value parses the value at json[i], which is matched by the node n,
+// or -1 if it is not matched by any path.
+func (t *pathTrie) value(json string, i int, n int, elem bool) (int, bool) {
+	var leaf, walk bool
+	if n >= 0 {
+		leaf = t.nodes[n].leaf && !t.nodes[n].found
+		walk = !leaf || t.nodes[n].pending > 1
+	}
+	for ; i < len(json); i++ {
+		var num, done bool
+		var val string
+		switch json[i] {
+		default:
+			continue
+		case '"':
+			var str string
+			var vesc, ok bool
+			i, val, str, vesc, ok = parseString(json, i, t.opts)
+			if !ok {
+				return i, false
+			}
+			if leaf {
+				if elem {
+					str = val[1 : len(val)-1]
+				}
+				if vesc {
+					str = unescape(str, t.opts)
+				}
+				return i, t.resolve(n, Result{Type: String, Raw: val, Str: str, Index: i - len(val)})
+			}
+		case '{', '[':
+			s := i
+			if walk {
+				if json[i] == '{' {
+					i, done = t.object(json, i+1, n)
+				} else {
+					i, done = t.array(json, i+1, n)
+				}
+				if done {
+					return i, true
+				}
+				val = json[s:i]
+			} else {
+				i, val = parseSquash(json, i)
+			}
+			if leaf {
+				return i, t.resolve(n, Result{Type: JSON, Raw: val, Index: s})
+			}
+		case 'n':
+			if i+1 < len(json) && json[i+1] != 'u' {
+				num = true
+				break
+			}
+			fallthrough
+		case 't', 'f':
+			vc := json[i]
+			i, val = parseLiteral(json, i)
+			if leaf {
+				value := Result{Raw: val, Index: i - len(val)}
+				switch vc {
+				case 't':
+					value.Type = True
+				case 'f':
+					value.Type = False
+				}
+				return i, t.resolve(n, value)
+			}
+		case '+', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9',
+			'i', 'I', 'N':
+			num = true
+		}
+		if num {
+			i, val = parseNumber(json, i)
+			if leaf {
+				value := Result{Type: Number, Raw: val, Index: i - len(val)}
+				value.Num, _ = strconv.ParseFloat(val, 64)
+				return i, t.resolve(n, value)
+			}
+		}
+		break
+	}
+	return i, false
+}
@@ -0,0 +1,88 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetManyMatchesGet(t *testing.T) {
+	json := `{"a":{"b":1},"a":{"c":[{"d":2},{"d":"x\"y"}]},"e":[{"f":{"g":4}},[5,true]],
+		"a.b":null,"0":{"1":-1.5e3},"k.l":"m","a.b":"dot"}`
+	paths := append([]string{"a", "a.b", "a.c", "a.c.1.d", "a.c.0", "a.c.01.d", "e.1.1", "e.0.f",
+		"e.0.f.g", "e.0.f.g", "a.b", `a\.b`, "0.1", "0", "k.l", `k\.l`, "a.c.1", "missing",
+		"a.c.2.d", "e.1", `\0.\1`}, samplePaths...)
+	for _, json := range append(sampleJSONs, json) {
+		exp := make([]Result, len(paths))
+		for i, path := range paths {
+			exp[i] = Get(json, path)
+		}
+		if got := GetMany(json, paths...); !reflect.DeepEqual(exp, got) {
+			for i := range exp {
+				if !reflect.DeepEqual(exp[i], got[i]) {
+					t.Fatalf("path %q: expected %#v, got %#v", paths[i], exp[i], got[i])
+				}
+			}
+		}
+		got := GetManyBytes([]byte(json), paths...)
+		for i := range exp {
+			if !reflect.DeepEqual(exp[i], got[i]) {
+				t.Fatalf("path %q: expected %#v, got %#v", paths[i], exp[i], got[i])
+			}
+		}
+	}
+}
+
+func TestGetManyEarlyExit(t *testing.T) {
+	// the walk stops once every path is found, so the malformed tail
+	// is never reached
+	json := `{"a":1,"b":{"c":[1,2,3]},"d":` + strings.Repeat("[", 1000)
+	res := GetMany(json, "a", "b.c.2")
+	assert(t, res[0].Int() == 1 && res[0].Index == 5)
+	assert(t, res[1].Int() == 3)
+	assert(t, len(GetManyBytes(nil, "a", "b")) == 2)
+}
+
+func BenchmarkGetMany(b *testing.B) {
+	var sb strings.Builder
+	var paths []string
+	sb.WriteString("{")
+	for i := 0; i < 30; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, `"field%d":{"tags":[%s],"id":%d}`, i, strings.Repeat(`"tag",`, 200)+`"end"`, i)
+		paths = append(paths, fmt.Sprintf("field%d.id", i))
+	}
+	sb.WriteString("}")
+	json := sb.String()
+	b.Run("Get", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, path := range paths {
+				Get(json, path)
+			}
+		}
+	})
+	b.Run("GetMany", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			GetMany(json, paths...)
+		}
+	})
+}
@@ -0,0 +1,352 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrOverflow is the error of a number out of the range of a Go type.
+	ErrOverflow = errors.New("gjson: number out of range")
+	// ErrFraction is the error of a number with a fraction converted to
+	// an integer.
+	ErrFraction = errors.New("gjson: number has a fraction")
+	// ErrType is the error of a value which is neither a number nor a string
+	// holding a json number.
+	ErrType = errors.New("gjson: value is not a number")
+)
+
+// ConversionError describes a value which cannot be converted to a Go type
+// by the checked accessors of Result, such as IntE.
+type ConversionError struct {
+	// Raw is the raw json of the value
+	Raw string
+	// Type is the Go type converted to, such as "int64"
+	Type string
+	// Err is ErrOverflow, ErrFraction, ErrType, ErrNotFound if the value
+	// does not exist, or the *time.ParseError of a time
+	Err error
+}
+
+func (e *ConversionError) Error() string {
+	raw := e.Raw
+	if len(raw) > 32 {
+		raw = raw[:32] + "..."
+	}
+	if raw != "" {
+		raw += " "
+	}
+	return "gjson: cannot convert " + raw + "to " + e.Type + ": " + strings.TrimPrefix(e.Err.Error(), "gjson: ")
+}
+
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// maxExactExp limits the exponent of the numbers converted to big.Int and
+// big.Rat, whose sizes grow with the exponent.
+const maxExactExp = 4096
+
+// Number returns the json number of a number, or of a string holding a json
+// number, and an empty json.Number otherwise. The number is the text of Raw,
+// thus it keeps the precision of the json.
+func (t Result) Number() json.Number {
+	s, ok := t.number()
+	if !ok {
+		return ""
+	}
+	return json.Number(s)
+}
+
+// BigInt returns the exact integer of a number, or of a string holding a json
+// number, such as 12345678901234567890 or 1.5e3. It returns false if the value
+// is not a number, is not an integer, or its exponent is too large unless the
+// number is zero.
+func (t Result) BigInt() (*big.Int, bool) {
+	s, ok := t.number()
+	if !ok {
+		return nil, false
+	}
+	if n, ok := new(big.Int).SetString(s, 10); ok {
+		return n, true
+	}
+	r, ok := parseRat(s)
+	if !ok || !r.IsInt() {
+		return nil, false
+	}
+	return r.Num(), true
+}
+
+// BigFloat returns the float of a number, or of a string holding a json
+// number, with a precision large enough for all the digits of the number.
+// It returns false if the value is not a number or it is out of the range of
+// a big.Float.
+func (t Result) BigFloat() (*big.Float, bool) {
+	s, ok := t.number()
+	if !ok {
+		return nil, false
+	}
+	// about 3.33 bits a digit, and the digits of the exponent are counted too
+	prec := uint(len(s))*10/3 + 1
+	if prec < 64 {
+		prec = 64
+	}
+	f, _, err := big.ParseFloat(s, 10, prec, big.ToNearestEven)
+	if err != nil || f.IsInf() {
+		return nil, false
+	}
+	return f, true
+}
+
+// Rat returns the exact rational of a number, or of a string holding a json
+// number, such as 12345678901234567.89. It returns false if the value is not
+// a number or its exponent is too large unless the number is zero.
+func (t Result) Rat() (*big.Rat, bool) {
+	s, ok := t.number()
+	if !ok {
+		return nil, false
+	}
+	return parseRat(s)
+}
+
+// number returns the text of a number, or of a string holding a json number.
+func (t Result) number() (string, bool) {
+	var s string
+	switch t.Type {
+	case Number:
+		s = t.Raw
+		if s == "" {
+			// calculated result
+			s = strconv.FormatFloat(t.Num, 'g', -1, 64)
+		}
+	case String:
+		s = t.Str
+	default:
+		return "", false
+	}
+	return s, isNumber(s)
+}
+
+// isNumber tells if s is a json number.
+func isNumber(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	i, ok := validnumber(stringBytes(s), 1)
+	return ok && i == len(s)
+}
+
+// parseRat parses a json number, or a number accepted by big.Rat, exactly.
+// It returns false if the exponent is larger than maxExactExp, unless all the
+// digits are zero.
+func parseRat(s string) (*big.Rat, bool) {
+	for i := 0; i < len(s); i++ {
+		if s[i] == 'e' || s[i] == 'E' {
+			exp, err := strconv.Atoi(s[i+1:])
+			if err != nil || exp > maxExactExp || exp < -maxExactExp {
+				if strings.IndexByte(s[:i], '0') >= 0 && strings.Trim(s[:i], "-0.") == "" {
+					return new(big.Rat), true
+				}
+				return nil, false
+			}
+			break
+		}
+	}
+	return new(big.Rat).SetString(s)
+}
+
+// compareNumbers compares the json numbers a and b exactly, and returns false
+// if they cannot be compared exactly, or either is not a json number.
+func compareNumbers(a, b string) (int, bool) {
+	if !isNumber(a) || !isNumber(b) {
+		return 0, false
+	}
+	ra, ok := parseRat(a)
+	if !ok {
+		return 0, false
+	}
+	rb, ok := parseRat(b)
+	if !ok {
+		return 0, false
+	}
+	return ra.Cmp(rb), true
+}
+
+// IntE returns the int64 of a number, or of a string holding a json number.
+// Unlike Int, it returns a *ConversionError if the value is out of range, has
+// a fraction or is not a number.
+func (t Result) IntE() (int64, error) {
+	return t.intE("int64", 64)
+}
+
+// Int32E returns the int32 of a number, see IntE.
+func (t Result) Int32E() (int32, error) {
+	n, err := t.intE("int32", 32)
+	return int32(n), err
+}
+
+// Int16E returns the int16 of a number, see IntE.
+func (t Result) Int16E() (int16, error) {
+	n, err := t.intE("int16", 16)
+	return int16(n), err
+}
+
+// Int8E returns the int8 of a number, see IntE.
+func (t Result) Int8E() (int8, error) {
+	n, err := t.intE("int8", 8)
+	return int8(n), err
+}
+
+// UintE returns the uint64 of a number, or of a string holding a json number.
+// Unlike Uint, it returns a *ConversionError if the value is negative, out of
+// range, has a fraction or is not a number.
+func (t Result) UintE() (uint64, error) {
+	return t.uintE("uint64", 64)
+}
+
+// Uint32E returns the uint32 of a number, see UintE.
+func (t Result) Uint32E() (uint32, error) {
+	n, err := t.uintE("uint32", 32)
+	return uint32(n), err
+}
+
+// Uint16E returns the uint16 of a number, see UintE.
+func (t Result) Uint16E() (uint16, error) {
+	n, err := t.uintE("uint16", 16)
+	return uint16(n), err
+}
+
+// Uint8E returns the uint8 of a number, see UintE.
+func (t Result) Uint8E() (uint8, error) {
+	n, err := t.uintE("uint8", 8)
+	return uint8(n), err
+}
+
+// FloatE returns the float64 of a number, or of a string holding a json
+// number. Unlike Float, it returns a *ConversionError if the value is out of
+// range or is not a number. A number too small is rounded to zero.
+func (t Result) FloatE() (float64, error) {
+	return t.floatE("float64", 64)
+}
+
+// Float32E returns the float32 of a number, see FloatE.
+func (t Result) Float32E() (float32, error) {
+	f, err := t.floatE("float32", 32)
+	return float32(f), err
+}
+
+func (t Result) intE(typ string, bits int) (int64, error) {
+	s, err := t.numberE(typ)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(s, 10, bits)
+	if err == nil {
+		return n, nil
+	}
+	if err.(*strconv.NumError).Err == strconv.ErrRange {
+		return 0, t.conversionError(typ, ErrOverflow)
+	}
+	// not an integer literal, such as 1.5e3
+	b, err := exactInt(s)
+	if err != nil {
+		return 0, t.conversionError(typ, err)
+	}
+	if !b.IsInt64() {
+		return 0, t.conversionError(typ, ErrOverflow)
+	}
+	n = b.Int64()
+	if bits < 64 && (n < -1<<(bits-1) || n >= 1<<(bits-1)) {
+		return 0, t.conversionError(typ, ErrOverflow)
+	}
+	return n, nil
+}
+
+func (t Result) uintE(typ string, bits int) (uint64, error) {
+	s, err := t.numberE(typ)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseUint(s, 10, bits)
+	if err == nil {
+		return n, nil
+	}
+	if err.(*strconv.NumError).Err == strconv.ErrRange {
+		return 0, t.conversionError(typ, ErrOverflow)
+	}
+	// not an integer literal, such as 1.5e3 or -1
+	b, err := exactInt(s)
+	if err != nil {
+		return 0, t.conversionError(typ, err)
+	}
+	if b.Sign() < 0 || b.BitLen() > bits {
+		return 0, t.conversionError(typ, ErrOverflow)
+	}
+	return b.Uint64(), nil
+}
+
+func (t Result) floatE(typ string, bits int) (float64, error) {
+	s, err := t.numberE(typ)
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(s, bits)
+	if err != nil {
+		return 0, t.conversionError(typ, ErrOverflow)
+	}
+	return f, nil
+}
+
+// numberE returns the text of a number, see number, or the error of
+// converting the value to typ.
+func (t Result) numberE(typ string) (string, error) {
+	if !t.Exists() {
+		return "", t.conversionError(typ, ErrNotFound)
+	}
+	s, ok := t.number()
+	if !ok {
+		return "", t.conversionError(typ, ErrType)
+	}
+	return s, nil
+}
+
+func (t Result) conversionError(typ string, err error) error {
+	return &ConversionError{Raw: t.Raw, Type: typ, Err: err}
+}
+
+// exactInt returns the integer of a json number, or ErrFraction or
+// ErrOverflow if it is not an integer or its exponent is too large.
+func exactInt(s string) (*big.Int, error) {
+	r, ok := parseRat(s)
+	if ok {
+		if !r.IsInt() {
+			return nil, ErrFraction
+		}
+		return r.Num(), nil
+	}
+	// the exponent is too large, which is a fraction if it is negative
+	e := strings.IndexAny(s, "eE")
+	if s[e+1] == '-' {
+		return nil, ErrFraction
+	}
+	return nil, ErrOverflow
+}
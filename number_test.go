@@ -0,0 +1,182 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBigNumbers(t *testing.T) {
+	json := `{"amount":12345678901234567.89,"id":123456789012345678901234567890,"neg":-98765432109876543210,
+		"exp":1.5e3,"frac":0.1,"huge":1e100000,"zero":0e99999,"negzero":-0.00e-99999,
+		"str":"12345678901234567.89","word":"abc","flag":true}`
+
+	res := Get(json, "amount")
+	assert(t, res.Number() == "12345678901234567.89")
+	r, ok := res.Rat()
+	assert(t, ok && r.FloatString(2) == "12345678901234567.89")
+	f, ok := res.BigFloat()
+	assert(t, ok && f.Text('f', 2) == "12345678901234567.89")
+	_, ok = res.BigInt()
+	assert(t, !ok)
+
+	n, ok := Get(json, "id").BigInt()
+	assert(t, ok && n.String() == "123456789012345678901234567890")
+	n, ok = Get(json, "neg").BigInt()
+	assert(t, ok && n.String() == "-98765432109876543210")
+	n, ok = Get(json, "exp").BigInt()
+	assert(t, ok && n.String() == "1500")
+	r, ok = Get(json, "frac").Rat()
+	assert(t, ok && r.String() == "1/10")
+
+	// the exponent is too large to be exact, but not for a big.Float
+	_, ok = Get(json, "huge").Rat()
+	assert(t, !ok)
+	_, ok = Get(json, "huge").BigInt()
+	assert(t, !ok)
+	f, ok = Get(json, "huge").BigFloat()
+	assert(t, ok && f.Text('g', 3) == "1e+100000")
+	assert(t, Get(json, "huge").Number() == "1e100000")
+	// unless all the digits are zero, like IntE
+	for _, path := range []string{"zero", "negzero"} {
+		n, ok = Get(json, path).BigInt()
+		assert(t, ok && n.Sign() == 0)
+		r, ok = Get(json, path).Rat()
+		assert(t, ok && r.Sign() == 0)
+		i, err := Get(json, path).IntE()
+		assert(t, err == nil && i == 0)
+	}
+
+	// strings holding numbers, like Int and Float
+	r, ok = Get(json, "str").Rat()
+	assert(t, ok && r.FloatString(2) == "12345678901234567.89")
+	for _, path := range []string{"word", "flag", "missing"} {
+		res := Get(json, path)
+		_, ok1 := res.BigInt()
+		_, ok2 := res.BigFloat()
+		_, ok3 := res.Rat()
+		assert(t, res.Number() == "" && !ok1 && !ok2 && !ok3)
+	}
+
+	// calculated results
+	n, ok = Get(`[1,2,3]`, "#").BigInt()
+	assert(t, ok && n.Int64() == 3)
+
+	// the json.Number keeps the text, which does not fit an int64
+	_, err := Get(json, "id").Number().Int64()
+	assert(t, err != nil)
+	x, err := Get(json, "exp").Number().Float64()
+	assert(t, err == nil && x == 1500)
+}
+
+func TestExactNumberQueries(t *testing.T) {
+	json := `{"items":[{"id":1,"amount":9007199254740993},{"id":2,"amount":9007199254740992},
+		{"id":3,"amount":0.30000000000000001},{"id":4,"amount":"9007199254740993"}]}`
+	exact := NewParser(Options{ExactNumbers: true})
+
+	// 9007199254740993 is 9007199254740992 as a float64, and strings are
+	// compared as strings
+	assert(t, Get(json, `items.#(amount==9007199254740993)#.id`).Raw == "[1,2,4]")
+	assert(t, exact.Get(json, `items.#(amount==9007199254740993)#.id`).Raw == "[1,4]")
+	assert(t, exact.Get(json, `items.#(amount>9007199254740992)#.id`).Raw == "[1,4]")
+	assert(t, exact.Get(json, `items.#(amount<=9007199254740992)#.id`).Raw == "[2,3]")
+	assert(t, exact.Get(json, `items.#(amount!=9007199254740992)#.id`).Raw == "[1,3,4]")
+	assert(t, Get(json, `items.#(amount==0.3)#.id`).Raw == "[3]")
+	assert(t, exact.Get(json, `items.#(amount==0.3)#.id`).Raw == "[]")
+	assert(t, exact.Get(json, `items.#(amount>0.3)#.id`).Raw == "[1,2,3,4]")
+	// a value which is not a number is compared as a float64
+	for _, v := range []string{"inf", "1/2", ".5", "+1", "0x10", "1_0", "01"} {
+		path := `items.#(amount>` + v + `)#.id`
+		assert(t, exact.Get(json, path).Raw == Get(json, path).Raw)
+	}
+
+	p, err := exact.Compile(`items.#(amount==9007199254740993).id`)
+	assert(t, err == nil && p.Get(json).Raw == "1")
+}
+
+func TestCheckedConversions(t *testing.T) {
+	json := `{"int":42,"neg":-129,"big":9223372036854775808,"min":-9223372036854775808,"exp":1.5e3,
+		"frac":1.5,"tiny":1e-5000,"zero":0e-5000,"huge":1e5000,"str":"300","word":"abc","flag":true,
+		"obj":{"a":1},"null":null}`
+
+	n, err := Get(json, "int").IntE()
+	assert(t, err == nil && n == 42)
+	n, err = Get(json, "min").IntE()
+	assert(t, err == nil && n == -9223372036854775808)
+	n, err = Get(json, "exp").IntE()
+	assert(t, err == nil && n == 1500)
+	n, err = Get(json, "zero").IntE()
+	assert(t, err == nil && n == 0)
+	n, err = Get(json, "str").IntE()
+	assert(t, err == nil && n == 300)
+	i8, err := Get(json, "neg").Int8E()
+	assert(t, errors.Is(err, ErrOverflow) && i8 == 0)
+	i16, err := Get(json, "neg").Int16E()
+	assert(t, err == nil && i16 == -129)
+	i32, err := Get(`-1.28e2`, "@this").Int32E()
+	assert(t, err == nil && i32 == -128)
+	i8, err = Get(`-1.28e2`, "@this").Int8E()
+	assert(t, err == nil && i8 == -128)
+	i8, err = Get(`1.28e2`, "@this").Int8E()
+	assert(t, errors.Is(err, ErrOverflow))
+
+	u, err := Get(json, "big").UintE()
+	assert(t, err == nil && u == 9223372036854775808)
+	u8, err := Get(json, "str").Uint8E()
+	assert(t, errors.Is(err, ErrOverflow) && u8 == 0)
+	u16, err := Get(json, "str").Uint16E()
+	assert(t, err == nil && u16 == 300)
+	u32, err := Get(json, "exp").Uint32E()
+	assert(t, err == nil && u32 == 1500)
+	_, err = Get(json, "neg").UintE()
+	assert(t, errors.Is(err, ErrOverflow))
+	u, err = Get(`-0`, "@this").UintE()
+	assert(t, err == nil && u == 0)
+
+	f, err := Get(json, "frac").FloatE()
+	assert(t, err == nil && f == 1.5)
+	f, err = Get(json, "tiny").FloatE()
+	assert(t, err == nil && f == 0)
+	_, err = Get(json, "huge").FloatE()
+	assert(t, errors.Is(err, ErrOverflow))
+	f32, err := Get(`3.5e38`, "@this").Float32E()
+	assert(t, errors.Is(err, ErrOverflow) && f32 == 0)
+	f32, err = Get(json, "exp").Float32E()
+	assert(t, err == nil && f32 == 1500)
+
+	for _, c := range []struct {
+		path string
+		err  error
+	}{
+		{"big", ErrOverflow}, {"huge", ErrOverflow}, {"frac", ErrFraction}, {"tiny", ErrFraction},
+		{"word", ErrType}, {"flag", ErrType}, {"obj", ErrType}, {"null", ErrType}, {"missing", ErrNotFound},
+	} {
+		_, err := Get(json, c.path).IntE()
+		var cerr *ConversionError
+		assert(t, errors.Is(err, c.err) && errors.As(err, &cerr) && cerr.Type == "int64")
+	}
+	_, err = Get(json, "frac").IntE()
+	assert(t, err.Error() == "gjson: cannot convert 1.5 to int64: number has a fraction")
+	_, err = Get(json, "word").UintE()
+	assert(t, err.Error() == `gjson: cannot convert "abc" to uint64: value is not a number`)
+	_, err = Get(json, "missing").FloatE()
+	assert(t, err.Error() == "gjson: cannot convert to float64: path not found")
+
+	// the lenient accessors are unchanged
+	assert(t, Get(json, "frac").Int() == 1 && Get(json, "word").Int() == 0 && Get(json, "flag").Int() == 1)
+}
@@ -0,0 +1,330 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cloudwego/gjson/internal/fast"
+)
+
+// the lines are sent to the workers in batches of these sizes at most
+const (
+	lineBatchSize  = 256
+	lineBatchBytes = 64 * 1024
+)
+
+// orderedBatchesPerWorker bounds the batches being evaluated or waiting for
+// the batches before them when the order of lines is preserved
+const orderedBatchesPerWorker = 4
+
+// ForEachLineParallel iterates through the lines of JSON Lines, like
+// ForEachLine does, using workers goroutines which call the iterator
+// concurrently, in no particular order. Zero workers means GOMAXPROCS.
+// The lineNo is the 1-based line number where the value starts.
+//
+// The values are split by the native skipping, and the iteration stops at
+// the first value which cannot be parsed. It also stops once an iterator
+// returns false, while the lines being evaluated by other workers are still
+// passed to the iterator.
+func ForEachLineParallel(json string, workers int, iterator func(line Result, lineNo int) bool) {
+	lr := lineRunner{iterator: iterator}
+	_ = lr.run(workers, newValueSplitter(json).next)
+}
+
+// ForEachLineParallelOrdered iterates through the lines of JSON Lines, like
+// ForEachLineParallel does, while preserving the order of lines. The mapper is
+// called concurrently by the workers, and the iterator is called with the
+// values returned by the mapper in the order of lines, in the calling
+// goroutine. The lines are split ahead by a few batches per worker at most,
+// which are kept while a slow line before them is mapped.
+func ForEachLineParallelOrdered(json string, workers int, mapper func(line Result, lineNo int) interface{},
+	iterator func(value interface{}, lineNo int) bool) {
+	lr := lineRunner{mapper: mapper, collect: iterator}
+	_ = lr.run(workers, newValueSplitter(json).next)
+}
+
+// ForEachLineReaderParallel iterates through the lines of JSON Lines read
+// from r, like ForEachLineReaderWithOptions does, using workers goroutines
+// which call the iterator concurrently, in no particular order.
+// Zero workers means GOMAXPROCS.
+//
+// OnInvalid of the options is also called concurrently. An error is returned
+// for a malformed line like ForEachLineReader does, while the lines being
+// evaluated by other workers are still passed to the iterator.
+func ForEachLineReaderParallel(r io.Reader, workers int, iterator func(line Result, lineNo int) bool, opts LineOptions) error {
+	lr := lineRunner{iterator: iterator, validate: true, onInvalid: opts.OnInvalid}
+	return lr.run(workers, newLineSplitter(r, opts.MaxLineSize).next)
+}
+
+// ForEachLineReaderParallelOrdered iterates through the lines of JSON Lines
+// read from r, like ForEachLineReaderParallel does, while preserving the order
+// of lines. See ForEachLineParallelOrdered for the mapper and the iterator.
+//
+// OnInvalid of the options is called in the order of lines too, and an error
+// is returned after all the lines before it are passed to the iterator.
+func ForEachLineReaderParallelOrdered(r io.Reader, workers int, mapper func(line Result, lineNo int) interface{},
+	iterator func(value interface{}, lineNo int) bool, opts LineOptions) error {
+	lr := lineRunner{mapper: mapper, collect: iterator, validate: true, onInvalid: opts.OnInvalid}
+	return lr.run(workers, newLineSplitter(r, opts.MaxLineSize).next)
+}
+
+type lineItem struct {
+	raw string
+	no  int
+	// err is a *LineError, or an error of reading
+	err error
+}
+
+type lineBatch struct {
+	seq   int
+	items []lineItem
+}
+
+type lineOutput struct {
+	value interface{}
+	no    int
+	err   error
+}
+
+type outputBatch struct {
+	seq  int
+	outs []lineOutput
+}
+
+// lineRunner evaluates the batches of lines in a pool of workers. The lines
+// are passed to the iterator by the workers, or passed to the mapper by the
+// workers and then to collect in order if the mapper is set.
+type lineRunner struct {
+	iterator  func(line Result, lineNo int) bool
+	mapper    func(line Result, lineNo int) interface{}
+	collect   func(value interface{}, lineNo int) bool
+	validate  bool
+	onInvalid func(err *LineError) bool
+
+	stopped int32
+	mux     sync.Mutex
+	err     error
+}
+
+func (lr *lineRunner) run(workers int, next func(seq int) (lineBatch, bool)) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	batches := make(chan lineBatch, workers)
+	outputs := make(chan outputBatch, workers)
+	// in order, a slow batch makes the collector keep the later ones, thus
+	// the splitter blocks once there are too many of them
+	var inflight chan struct{}
+	if lr.mapper != nil {
+		inflight = make(chan struct{}, workers*orderedBatchesPerWorker)
+	}
+	go func() {
+		for seq := 0; atomic.LoadInt32(&lr.stopped) == 0; seq++ {
+			b, ok := next(seq)
+			if len(b.items) > 0 {
+				if inflight != nil {
+					inflight <- struct{}{}
+				}
+				batches <- b
+			}
+			if !ok {
+				break
+			}
+		}
+		close(batches)
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for b := range batches {
+				if lr.mapper != nil {
+					outputs <- lr.mapBatch(b)
+				} else {
+					lr.iterateBatch(b)
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(outputs)
+	}()
+
+	// collect the outputs in order, which are received until closed so that
+	// the workers never block
+	pending := make(map[int]outputBatch)
+	seq := 0
+	for b := range outputs {
+		pending[b.seq] = b
+		for b, ok := pending[seq]; ok; b, ok = pending[seq] {
+			delete(pending, seq)
+			seq++
+			<-inflight
+			for _, out := range b.outs {
+				if atomic.LoadInt32(&lr.stopped) != 0 {
+					break
+				}
+				if out.err != nil {
+					lr.invalid(out.err)
+				} else if !lr.collect(out.value, out.no) {
+					lr.stop(nil)
+				}
+			}
+		}
+	}
+	return lr.err
+}
+
+func (lr *lineRunner) iterateBatch(b lineBatch) {
+	for _, it := range b.items {
+		if atomic.LoadInt32(&lr.stopped) != 0 {
+			return
+		}
+		res, err := lr.eval(it)
+		if err != nil {
+			lr.invalid(err)
+		} else if !lr.iterator(res, it.no) {
+			lr.stop(nil)
+		}
+	}
+}
+
+func (lr *lineRunner) mapBatch(b lineBatch) outputBatch {
+	outs := make([]lineOutput, 0, len(b.items))
+	for _, it := range b.items {
+		if atomic.LoadInt32(&lr.stopped) != 0 {
+			break
+		}
+		res, err := lr.eval(it)
+		out := lineOutput{no: it.no, err: err}
+		if err == nil {
+			out.value = lr.mapper(res, it.no)
+		}
+		outs = append(outs, out)
+	}
+	return outputBatch{seq: b.seq, outs: outs}
+}
+
+func (lr *lineRunner) eval(it lineItem) (Result, error) {
+	if it.err != nil {
+		return Result{}, it.err
+	}
+	if lr.validate {
+		if err := validate(stringBytes(it.raw), false); err != nil {
+			return Result{}, &LineError{Line: it.no, Err: err}
+		}
+	}
+	return Parse(it.raw), nil
+}
+
+// invalid skips a malformed line if OnInvalid tells so, otherwise it stops
+// with the error.
+func (lr *lineRunner) invalid(err error) {
+	if lerr, ok := err.(*LineError); ok && lr.onInvalid != nil && lr.onInvalid(lerr) {
+		return
+	}
+	lr.stop(err)
+}
+
+func (lr *lineRunner) stop(err error) {
+	lr.mux.Lock()
+	if lr.err == nil && atomic.LoadInt32(&lr.stopped) == 0 {
+		lr.err = err
+	}
+	atomic.StoreInt32(&lr.stopped, 1)
+	lr.mux.Unlock()
+}
+
+// valueSplitter splits a json into its values by the native skipping.
+type valueSplitter struct {
+	json string
+	pos  int
+	// line is the line number of pos
+	line int
+}
+
+func newValueSplitter(json string) *valueSplitter {
+	return &valueSplitter{json: json, line: 1}
+}
+
+// next returns the next batch of values, and reports false at the end.
+func (s *valueSplitter) next(seq int) (lineBatch, bool) {
+	b := lineBatch{seq: seq}
+	for size := 0; size < lineBatchBytes && len(b.items) < lineBatchSize; {
+		start, end, err := fast.SkipValue(s.json, s.pos)
+		if err != nil {
+			return b, false
+		}
+		s.line += strings.Count(s.json[s.pos:start], "\n")
+		b.items = append(b.items, lineItem{raw: s.json[start:end], no: s.line})
+		s.line += strings.Count(s.json[start:end], "\n")
+		s.pos = end
+		size += end - start
+	}
+	return b, true
+}
+
+// lineSplitter splits the json read from a reader into its lines.
+type lineSplitter struct {
+	s   *lineScanner
+	buf []byte
+}
+
+func newLineSplitter(r io.Reader, max int) *lineSplitter {
+	return &lineSplitter{s: newLineScanner(r, max)}
+}
+
+// next returns the next batch of lines, which ends at an error, and reports
+// false at the end or an error of reading.
+func (s *lineSplitter) next(seq int) (lineBatch, bool) {
+	b := lineBatch{seq: seq}
+	s.buf = s.buf[:0]
+	var ends []int
+	var err error
+	for len(s.buf) < lineBatchBytes && len(ends) < lineBatchSize {
+		var line []byte
+		var no int
+		line, no, err = s.s.scan()
+		if err != nil || line == nil {
+			break
+		}
+		s.buf = append(s.buf, line...)
+		ends = append(ends, len(s.buf))
+		b.items = append(b.items, lineItem{no: no})
+	}
+	// the lines share a string of the batch
+	str := string(s.buf)
+	start := 0
+	for i, end := range ends {
+		b.items[i].raw = str[start:end]
+		start = end
+	}
+	if err != nil {
+		b.items = append(b.items, lineItem{no: s.s.lineNo, err: err})
+		_, isLineErr := err.(*LineError)
+		return b, isLineErr
+	}
+	return b, len(ends) == lineBatchSize || len(s.buf) >= lineBatchBytes
+}
@@ -0,0 +1,217 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func makeJSONLines(n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		switch i % 4 {
+		case 0:
+			fmt.Fprintf(&sb, "{\"id\":%d,\"name\":\"n%d\"}\n", i, i)
+		case 1:
+			fmt.Fprintf(&sb, "  [%d, {\"id\":%d}]\r\n\n", i, i)
+		case 2:
+			fmt.Fprintf(&sb, "%d\n", i)
+		default:
+			fmt.Fprintf(&sb, "\"%d\"\n", i)
+		}
+	}
+	return sb.String()
+}
+
+type parallelLine struct {
+	no  int
+	raw string
+}
+
+func sortLines(lines []parallelLine) {
+	sort.Slice(lines, func(i, j int) bool { return lines[i].no < lines[j].no })
+}
+
+func TestForEachLineParallel(t *testing.T) {
+	jsonl := makeJSONLines(5000)
+	var want []parallelLine
+	err := ForEachLineReader(strings.NewReader(jsonl), func(res Result, lineNo int) bool {
+		want = append(want, parallelLine{lineNo, res.Raw})
+		return true
+	})
+	assert(t, err == nil && len(want) == 5000)
+
+	for _, workers := range []int{0, 1, 3} {
+		var mux sync.Mutex
+		var got []parallelLine
+		ForEachLineParallel(jsonl, workers, func(res Result, lineNo int) bool {
+			mux.Lock()
+			got = append(got, parallelLine{lineNo, res.Raw})
+			mux.Unlock()
+			return true
+		})
+		sortLines(got)
+		assert(t, fmt.Sprint(got) == fmt.Sprint(want))
+
+		got = got[:0]
+		err = ForEachLineReaderParallel(iotest.HalfReader(strings.NewReader(jsonl)), workers, func(res Result, lineNo int) bool {
+			mux.Lock()
+			got = append(got, parallelLine{lineNo, res.Raw})
+			mux.Unlock()
+			return true
+		}, LineOptions{})
+		sortLines(got)
+		assert(t, err == nil && fmt.Sprint(got) == fmt.Sprint(want))
+
+		got = got[:0]
+		ForEachLineParallelOrdered(jsonl, workers, func(res Result, lineNo int) interface{} {
+			return parallelLine{lineNo, res.Raw}
+		}, func(v interface{}, lineNo int) bool {
+			got = append(got, v.(parallelLine))
+			return true
+		})
+		assert(t, fmt.Sprint(got) == fmt.Sprint(want))
+
+		got = got[:0]
+		err = ForEachLineReaderParallelOrdered(strings.NewReader(jsonl), workers, func(res Result, lineNo int) interface{} {
+			return parallelLine{lineNo, res.Raw}
+		}, func(v interface{}, lineNo int) bool {
+			got = append(got, v.(parallelLine))
+			return true
+		}, LineOptions{})
+		assert(t, err == nil && fmt.Sprint(got) == fmt.Sprint(want))
+	}
+
+	// like ForEachLine, stopping at a malformed value
+	var n int
+	ForEachLineParallel("1 2\n{\"a\": 3\n4", 2, func(res Result, lineNo int) bool {
+		n++
+		return true
+	})
+	assert(t, n == 2)
+}
+
+func TestForEachLineParallelStop(t *testing.T) {
+	jsonl := makeJSONLines(50000)
+	var mux sync.Mutex
+	var n int
+	ForEachLineParallel(jsonl, 4, func(res Result, lineNo int) bool {
+		mux.Lock()
+		defer mux.Unlock()
+		n++
+		return n < 10
+	})
+	// the lines being evaluated by other workers may still be iterated
+	assert(t, n >= 10 && n < 50000)
+
+	var got []int
+	err := ForEachLineReaderParallelOrdered(strings.NewReader(jsonl), 4, func(res Result, lineNo int) interface{} {
+		return lineNo
+	}, func(v interface{}, lineNo int) bool {
+		got = append(got, v.(int))
+		return len(got) < 10
+	}, LineOptions{})
+	assert(t, err == nil && len(got) == 10 && got[9] == 12)
+}
+
+func TestParallelOrderedBounded(t *testing.T) {
+	const workers, lines = 2, 1000
+	var mux sync.Mutex
+	var split int
+	next := func(seq int) (lineBatch, bool) {
+		mux.Lock()
+		split++
+		mux.Unlock()
+		return lineBatch{seq: seq, items: []lineItem{{raw: "1", no: seq + 1}}}, seq+1 < lines
+	}
+	// the first line is slow, so the later ones wait for it
+	release := make(chan struct{})
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		mux.Lock()
+		defer mux.Unlock()
+		if split > workers*orderedBatchesPerWorker+1 {
+			t.Errorf("%d batches split while the first one is slow", split)
+		}
+		close(release)
+	}()
+	var got []int
+	lr := lineRunner{mapper: func(res Result, lineNo int) interface{} {
+		if lineNo == 1 {
+			<-release
+		}
+		return lineNo
+	}, collect: func(v interface{}, lineNo int) bool {
+		got = append(got, v.(int))
+		return true
+	}}
+	assert(t, lr.run(workers, next) == nil && len(got) == lines)
+	for i, no := range got {
+		assert(t, no == i+1)
+	}
+}
+
+func TestForEachLineReaderParallelErrors(t *testing.T) {
+	jsonl := "{\"a\":1}\r\n\n  [2]\n{\"a\":\n\"x\"\n  {\"long\":\"" + strings.Repeat("y", 64) + "\"}\n4"
+	mapper := func(res Result, lineNo int) interface{} {
+		return parallelLine{lineNo, res.Raw}
+	}
+	var got []parallelLine
+	iterator := func(v interface{}, lineNo int) bool {
+		got = append(got, v.(parallelLine))
+		return true
+	}
+
+	err := ForEachLineReaderParallelOrdered(strings.NewReader(jsonl), 2, mapper, iterator, LineOptions{})
+	var lerr *LineError
+	assert(t, errors.As(err, &lerr) && lerr.Line == 4)
+	assert(t, len(got) == 2 && got[0] == parallelLine{1, `{"a":1}`} && got[1] == parallelLine{3, `[2]`})
+
+	got = nil
+	var invalid []int
+	err = ForEachLineReaderParallelOrdered(strings.NewReader(jsonl), 2, mapper, iterator, LineOptions{
+		MaxLineSize: 32,
+		OnInvalid: func(err *LineError) bool {
+			invalid = append(invalid, err.Line)
+			return err.Line < 6 || errors.Is(err, ErrLineTooLong)
+		},
+	})
+	assert(t, err == nil)
+	assert(t, len(invalid) == 2 && invalid[0] == 4 && invalid[1] == 6)
+	assert(t, len(got) == 4 && got[2] == parallelLine{5, `"x"`} && got[3] == parallelLine{7, `4`})
+
+	var mux sync.Mutex
+	var lines []int
+	err = ForEachLineReaderParallel(strings.NewReader(jsonl), 2, func(res Result, lineNo int) bool {
+		mux.Lock()
+		lines = append(lines, lineNo)
+		mux.Unlock()
+		return true
+	}, LineOptions{})
+	assert(t, errors.As(err, &lerr) && lerr.Line == 4)
+
+	err = ForEachLineReaderParallel(iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader(jsonl))), 2,
+		func(res Result, lineNo int) bool { return true }, LineOptions{})
+	assert(t, err == iotest.ErrTimeout)
+}
@@ -0,0 +1,137 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import "io"
+
+// Parser searches json with its own Options, instead of the settings of the
+// package-level functions, which are process-wide. A Parser is safe for
+// concurrent use.
+//
+// The options apply to the searching done by the Parser. The methods of a
+// Result returned by the Parser, such as Result.Get, use the package-level
+// settings.
+type Parser struct {
+	opts Options
+}
+
+// NewParser returns a Parser using the options.
+func NewParser(opts Options) *Parser {
+	return &Parser{opts: opts}
+}
+
+// Options returns the options of the Parser.
+func (p *Parser) Options() Options {
+	return p.opts
+}
+
+// Get searches json for the specified path, see Get.
+func (p *Parser) Get(json, path string) Result {
+	return getWith(json, path, &p.opts)
+}
+
+// GetBytes searches json for the specified path, see GetBytes.
+func (p *Parser) GetBytes(json []byte, path string) Result {
+	return getBytes(json, path, &p.opts)
+}
+
+// GetE searches json for the specified path, and tells why a value is not
+// found, see GetE.
+func (p *Parser) GetE(json, path string) (Result, error) {
+	return getE(json, path, &p.opts)
+}
+
+// GetBytesE searches json for the specified path, see Parser.GetE.
+func (p *Parser) GetBytesE(json []byte, path string) (Result, error) {
+	return getBytesE(json, path, &p.opts)
+}
+
+// GetInto searches json for the specified path, and decodes the value into v,
+// see GetInto.
+func (p *Parser) GetInto(json, path string, v interface{}) error {
+	return getInto(json, path, v, &p.opts)
+}
+
+// GetMany searches json for the multiple paths, see GetMany.
+func (p *Parser) GetMany(json string, path ...string) []Result {
+	return getMany(json, path, &p.opts)
+}
+
+// GetManyBytes searches json for the multiple paths, see GetManyBytes.
+func (p *Parser) GetManyBytes(json []byte, path ...string) []Result {
+	return getManyBytes(json, path, &p.opts)
+}
+
+// GetReader searches the json read from r for the multiple paths,
+// see GetReader.
+func (p *Parser) GetReader(r io.Reader, path ...string) ([]Result, error) {
+	return getReader(r, path, &p.opts)
+}
+
+// Parse parses the json and returns a result, see Parse.
+func (p *Parser) Parse(json string) Result {
+	return parse(json, &p.opts)
+}
+
+// Compile parses a path into a Path, which searches json with the options
+// of the Parser, see Compile.
+func (p *Parser) Compile(path string) (*Path, error) {
+	return compile(path, &p.opts)
+}
+
+// ValidatePath returns nil if the path is well-formed, otherwise a
+// *PathSyntaxError, see ValidatePath. The modifiers are not checked if they
+// are disabled, since they are object keys then.
+func (p *Parser) ValidatePath(path string) error {
+	return validatePath(path, &p.opts)
+}
+
+// Valid returns true if the input is valid json, see Valid. The strings must
+// be valid UTF-8 too if ValidateUTF8 is set, see ValidStrict.
+func (p *Parser) Valid(json string) bool {
+	if p.opts.ValidateUTF8 {
+		return ValidStrict(json)
+	}
+	return Valid(json)
+}
+
+// ValidBytes returns true if the input is valid json, see Parser.Valid.
+func (p *Parser) ValidBytes(json []byte) bool {
+	if p.opts.ValidateUTF8 {
+		return ValidStrictBytes(json)
+	}
+	return ValidBytes(json)
+}
+
+// Validate returns nil if the input is valid json, otherwise a *SyntaxError,
+// see Validate. The strings must be valid UTF-8 too if ValidateUTF8 is set.
+func (p *Parser) Validate(json string) error {
+	return validate(stringBytes(json), p.opts.ValidateUTF8)
+}
+
+// ValidateBytes returns nil if the input is valid json, otherwise a
+// *SyntaxError, see Parser.Validate.
+func (p *Parser) ValidateBytes(json []byte) error {
+	return validate(json, p.opts.ValidateUTF8)
+}
+
+// GetWithOptions searches json for the specified path using the options,
+// see Get. A Parser is preferred for searching many times with the same
+// options.
+func GetWithOptions(json, path string, opts Options) Result {
+	return getWith(json, path, &opts)
+}
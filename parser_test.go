@@ -0,0 +1,118 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cloudwego/gjson/internal/fast"
+)
+
+func TestParserMatchesGet(t *testing.T) {
+	for _, opts := range []Options{{}, {FastPath: true}, {MatchLimit: 1}} {
+		p := NewParser(opts)
+		assert(t, p.Options() == opts)
+		for _, json := range sampleJSONs {
+			for _, path := range samplePaths {
+				exp := Get(json, path)
+				if got := p.Get(json, path); !reflect.DeepEqual(exp, got) {
+					t.Fatalf("%+v: path %q: expected %#v, got %#v", opts, path, exp, got)
+				}
+				if got := p.GetBytes([]byte(json), path); !reflect.DeepEqual(exp, got) {
+					t.Fatalf("%+v: path %q: expected %#v, got %#v", opts, path, exp, got)
+				}
+				if got := GetWithOptions(json, path, opts); !reflect.DeepEqual(exp, got) {
+					t.Fatalf("%+v: path %q: expected %#v, got %#v", opts, path, exp, got)
+				}
+				if c, err := p.Compile(path); err == nil {
+					if got := c.Get(json); !reflect.DeepEqual(exp, got) {
+						t.Fatalf("%+v: compiled path %q: expected %#v, got %#v", opts, path, exp, got)
+					}
+				}
+			}
+			assert(t, reflect.DeepEqual(GetMany(json, samplePaths...), p.GetMany(json, samplePaths...)))
+			assert(t, reflect.DeepEqual(GetManyBytes([]byte(json), samplePaths...),
+				p.GetManyBytes([]byte(json), samplePaths...)))
+			assert(t, reflect.DeepEqual(Parse(json), p.Parse(json)))
+		}
+	}
+}
+
+func TestParserOptions(t *testing.T) {
+	json := "{\"name\":\"a\\u003cb\",\"bad\":\"\xff\",\"children\":[1,2]}"
+
+	p := NewParser(Options{DisableModifiers: true})
+	assert(t, !p.Get(json, "children|@reverse").Exists())
+	assert(t, !p.Get(json, "@this").Exists())
+	assert(t, NewParser(Options{}).Get(json, "children|@reverse").Raw == "[2,1]")
+	c, err := p.Compile("children.@reverse")
+	assert(t, err == nil && !c.Get(json).Exists())
+
+	p = NewParser(Options{DisableEscapeHTML: true})
+	assert(t, p.Get(json, "{a<b:name}").Raw == `{"a<b":"a\u003cb"}`)
+	assert(t, Get(json, "{a<b:name}").Raw == `{"a\u003cb":"a\u003cb"}`)
+	// the modifiers read the package variable
+	assert(t, Get(`["<"]`, "@tostr").Raw == `"[\"\u003c\"]"`)
+	assert(t, p.Get(`["<"]`, "@tostr").Raw == `"[\"\u003c\"]"`)
+	DisableEscapeHTML = true
+	assert(t, NewParser(Options{}).Get(`["<"]`, "@tostr").Raw == `"[\"<\"]"`)
+	DisableEscapeHTML = false
+
+	for _, opts := range []Options{{ValidateUTF8: true}, {ValidateUTF8: true, FastPath: true}} {
+		p = NewParser(opts)
+		assert(t, !p.Get(json, "bad").Exists())
+		assert(t, p.Get(json, "name").String() == "a<b")
+		assert(t, p.GetMany(json, "bad", "name")[1].String() == "a<b")
+	}
+	assert(t, NewParser(Options{}).Get(json, "bad").Exists())
+
+	p = NewParser(Options{FastString: true})
+	assert(t, p.Get(json, "bad").Exists())
+	assert(t, p.Get(json, "name").String() == "a<b")
+}
+
+func TestDefaultOptions(t *testing.T) {
+	defer func(v bool) { DisableModifiers = v }(DisableModifiers)
+	DisableModifiers = true
+	opts := DefaultOptions()
+	assert(t, opts.DisableModifiers && opts.MatchLimit == defaultMatchLimit)
+	assert(t, !NewParser(opts).Get(`[1,2]`, "@reverse").Exists())
+	assert(t, (&Options{}).matchLimit() == defaultMatchLimit)
+	assert(t, (&Options{MatchLimit: 5}).matchLimit() == 5)
+}
+
+func TestPathCache(t *testing.T) {
+	defer SetPathCache(fast.DefaultPathCacheSize, CacheCLOCK)
+	json := `{"a":{"b":[1,2,3]},"c":"d"}`
+	for _, policy := range []CachePolicy{CacheCLOCK, CacheLRU} {
+		SetPathCache(2, policy)
+		p := NewParser(Options{FastPath: true})
+		assert(t, p.Get(json, "a.b.1").Int() == 2)
+		assert(t, p.Get(json, "a.b.1").Int() == 2)
+		assert(t, p.Get(json, "c").String() == "d")
+		assert(t, p.Get(json, "a.b.#").Int() == 3)
+		stats := GetPathCacheStats()
+		assert(t, stats.Hits == 1 && stats.Misses == 3 && stats.Evictions == 1)
+		assert(t, stats.Size == 2 && stats.Capacity == 2)
+	}
+
+	SetPathCache(0, CacheLRU)
+	assert(t, NewParser(Options{FastPath: true}).Get(json, "c").String() == "d")
+	stats := GetPathCacheStats()
+	assert(t, stats.Hits == 0 && stats.Size == 0)
+}
@@ -0,0 +1,401 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"strconv"
+	"unsafe"
+
+	"github.com/cloudwego/gjson/internal/fast"
+)
+
+// Path is a compiled GJSON path, which can be searched in many json documents
+// without parsing the path again. A Path is safe for concurrent use.
+//
+// Modifiers are resolved when the path is compiled, thus later calls of
+// AddModifier or changes of DisableModifiers do not affect a compiled Path.
+type Path struct {
+	path string
+	// options of the Parser compiling the path, or nil
+	opts *Options
+	// parsed simple path for fast.Get()
+	fast []interface{}
+	// parsed literal prefix for fast.Get(), and the rest of a plain path
+	prefix []interface{}
+	rest   *pathNode
+	// kind of the path:
+	//   0:        a plain path
+	//   '.':      a JSON Lines path, such as `..#`
+	//   '@':      a modifier
+	//   '!':      a static value
+	//   '[', '{': a multipath
+	kind byte
+	// modifier function
+	fn func(json, arg string) string
+	// modifier arguments, or the static value
+	args string
+	// multipath selectors
+	sels []pathSelector
+	// the path following a modifier, a static value or a multipath
+	next *Path
+	// the first component of a plain path
+	node *pathNode
+}
+
+type pathSelector struct {
+	sub  subSelector
+	path *Path
+}
+
+// pathNode is a compiled component of a plain path.
+// A component is parsed both as an object key and as an array key,
+// since which one is used depends on the json being searched.
+type pathNode struct {
+	path string
+
+	obj     objectPathResult
+	objNext *pathNode // obj.path
+	objPipe *Path     // obj.pipe
+
+	arr      arrayPathResult
+	arrNext  *pathNode // arr.path
+	arrPipe  *Path     // arr.pipe
+	query    *Path     // arr.query.path
+	more     *Path     // arr.path after a query, with its pipe split off
+	morePipe *Path
+	alog     *Path // arr.alogkey, with its pipe split off
+	alogPipe *Path
+}
+
+// PathSyntaxError describes a malformed path.
+type PathSyntaxError struct {
+	// Path is the malformed path
+	Path string
+	// Offset is the position in Path where the error was found
+	Offset int
+	// Msg describes the error
+	Msg string
+}
+
+func (e *PathSyntaxError) Error() string {
+	return "gjson: " + e.Msg + " at offset " + strconv.Itoa(e.Offset) +
+		" of path " + strconv.Quote(e.Path)
+}
+
+// Compile parses a path into a Path, which returns the same results as
+// Get(json, path) does. An error is returned if the path is malformed, as
+// reported by ValidatePath.
+//
+//	p, err := gjson.Compile("friends.#(last==\"Murphy\")#.first")
+//	if err != nil {
+//		return err
+//	}
+//	value := p.Get(json)
+func Compile(path string) (*Path, error) {
+	return compile(path, nil)
+}
+
+func compile(path string, opts *Options) (*Path, error) {
+	if err := validatePath(path, opts); err != nil {
+		return nil, err
+	}
+	cp := pathCompiler{src: path, opts: opts, nodes: make(map[string]*pathNode)}
+	return cp.path(path)
+}
+
+// MustCompile is like Compile but panics if the path is malformed.
+func MustCompile(path string) *Path {
+	p, err := Compile(path)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the source text of the path.
+func (p *Path) String() string {
+	return p.path
+}
+
+// Get searches json for the compiled path.
+//
+// This function expects that the json is well-formed, and does not validate.
+// Invalid json will not panic, but it may return back unexpected results.
+func (p *Path) Get(json string) Result {
+	if p.fast != nil && p.opts.fastPath() {
+		if ret, ok := getFast(json, p.fast, p.opts); ok {
+			return ret
+		}
+	} else if p.prefix != nil && p.opts.fastPath() {
+		if ret, ok := getFastPrefix(json, p.prefix, p.rest.path, p.rest, p.opts); ok {
+			return ret
+		}
+	}
+	switch p.kind {
+	case '@', '!':
+		rjson := p.args
+		if p.kind == '@' {
+			rjson = p.fn(json, p.args)
+		}
+		if p.next != nil {
+			res := p.next.Get(rjson)
+			res.Index = 0
+			res.Indexes = nil
+			return res
+		}
+		return parse(rjson, p.opts)
+	case '[', '{':
+		var b []byte
+		b = append(b, p.kind)
+		var i int
+		for _, sel := range p.sels {
+			res := sel.path.Get(json)
+			if res.Exists() {
+				b = appendSubSelection(b, p.kind, i, sel.sub, res, p.opts)
+				i++
+			}
+		}
+		b = append(b, p.kind+2)
+		var res Result
+		res.Raw = string(b)
+		res.Type = JSON
+		if p.next != nil {
+			res = res.getPath(p.next)
+		}
+		res.Index = 0
+		return res
+	}
+	var c = &parseContext{json: json, opts: p.opts}
+	if p.kind == '.' {
+		c.lines = true
+		parseArray(c, 0, p.path[2:], p.node)
+	} else {
+		for i := 0; i < len(c.json); i++ {
+			if c.json[i] == '{' {
+				parseObject(c, i+1, p.path, p.node)
+				break
+			}
+			if c.json[i] == '[' {
+				parseArray(c, i+1, p.path, p.node)
+				break
+			}
+		}
+	}
+	return c.result()
+}
+
+// GetBytes searches json for the compiled path.
+// If working with bytes, this method preferred over p.Get(string(data))
+func (p *Path) GetBytes(json []byte) Result {
+	var result Result
+	if json != nil {
+		// unsafe cast to string
+		result = copyResult(p.Get(*(*string)(unsafe.Pointer(&json))))
+	}
+	return result
+}
+
+// getPath searches result for the compiled path, like t.Get(p.String()).
+func (t Result) getPath(p *Path) Result {
+	return t.relative(p.Get(t.Raw))
+}
+
+type pathCompiler struct {
+	// src is the path passed to Compile
+	src  string
+	opts *Options
+	// nodes caches compiled components by their path,
+	// since the object and the array parsing of a component
+	// usually leave the same remaining path.
+	nodes map[string]*pathNode
+}
+
+func (cp *pathCompiler) errorAt(sub string, msg string) error {
+	return &PathSyntaxError{Path: cp.src, Offset: offsetOf(cp.src, sub), Msg: msg}
+}
+
+// queryExpr compiles the paths of the comparisons in a compound query.
+func (cp *pathCompiler) queryExpr(x *queryExpr) (err error) {
+	switch {
+	case x == nil:
+		return nil
+	case x.op == 0:
+		if x.query, err = cp.path(x.path); err != nil {
+			return err
+		}
+		return cp.queryRef(x.ref)
+	}
+	if err = cp.queryExpr(x.left); err != nil {
+		return err
+	}
+	return cp.queryExpr(x.right)
+}
+
+// queryRef compiles the path of a reference in a query.
+func (cp *pathCompiler) queryRef(r *queryRef) (err error) {
+	if r != nil {
+		r.query, err = cp.path(r.path)
+	}
+	return err
+}
+
+func (cp *pathCompiler) path(path string) (p *Path, err error) {
+	p = &Path{path: path, opts: cp.opts, fast: fast.ParsePaths(path)}
+	if len(path) > 1 {
+		if (path[0] == '@' && cp.opts.modifiers()) || path[0] == '!' {
+			var ok bool
+			var rest string
+			if path[0] == '@' {
+				rest, p.fn, p.args, ok = parseModifier(path)
+			} else {
+				rest, p.args, ok = parseStatic(path)
+			}
+			if ok {
+				p.kind = path[0]
+				if len(rest) > 0 && (rest[0] == '|' || rest[0] == '.') {
+					if p.next, err = cp.path(rest[1:]); err != nil {
+						return nil, err
+					}
+				}
+				return p, nil
+			}
+		}
+		if path[0] == '[' || path[0] == '{' {
+			subs, rest, ok := parseSubSelectors(path)
+			if !ok {
+				return nil, cp.errorAt(path, "unclosed multipath")
+			}
+			if len(rest) == 0 || rest[0] == '|' || rest[0] == '.' {
+				p.kind = path[0]
+				p.sels = make([]pathSelector, len(subs))
+				for i, sub := range subs {
+					p.sels[i].sub = sub
+					if p.sels[i].path, err = cp.path(sub.path); err != nil {
+						return nil, err
+					}
+				}
+				if len(rest) > 0 {
+					if p.next, err = cp.path(rest[1:]); err != nil {
+						return nil, err
+					}
+				}
+				return p, nil
+			}
+			// like getWith, the data after the multipath is searched
+			path = rest
+		}
+	}
+	if len(path) >= 2 && path[0] == '.' && path[1] == '.' {
+		p.kind = '.'
+		p.node, err = cp.node(path[2:])
+	} else {
+		p.node, err = cp.node(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.fast == nil && p.kind == 0 {
+		var rest string
+		if p.prefix, rest = fast.ParsePrefix(p.path); p.prefix != nil {
+			if p.rest, err = cp.node(rest); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p, nil
+}
+
+func (cp *pathCompiler) node(path string) (n *pathNode, err error) {
+	if n = cp.nodes[path]; n != nil {
+		return n, nil
+	}
+	n = &pathNode{path: path, obj: parseObjectPath(path, cp.opts), arr: parseArrayPath(path, cp.opts)}
+	cp.nodes[path] = n
+
+	if n.obj.more {
+		if n.objNext, err = cp.node(n.obj.path); err != nil {
+			return nil, err
+		}
+	} else if n.obj.piped {
+		if n.objPipe, err = cp.path(n.obj.pipe); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case n.arr.alogok:
+		if n.alog, n.alogPipe, err = cp.splitPipe(n.arr.alogkey); err != nil {
+			return nil, err
+		}
+	case n.arr.query.on:
+		if _, _, _, _, _, _, ok := parseQuery(path); !ok {
+			return nil, cp.errorAt(path, "unclosed query")
+		}
+		if n.query, err = cp.path(n.arr.query.path); err != nil {
+			return nil, err
+		}
+		if err = cp.queryRef(n.arr.query.ref); err != nil {
+			return nil, err
+		}
+		if err = cp.queryExpr(n.arr.query.expr); err != nil {
+			return nil, err
+		}
+		if n.arr.more {
+			if n.more, n.morePipe, err = cp.splitPipe(n.arr.path); err != nil {
+				return nil, err
+			}
+		}
+	case n.arr.more && !n.arr.arrch:
+		if n.arrNext, err = cp.node(n.arr.path); err != nil {
+			return nil, err
+		}
+	}
+	if !n.arr.more && n.arr.piped {
+		if n.arrPipe, err = cp.path(n.arr.pipe); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// splitPipe compiles the left and right side of a possibly piped path.
+func (cp *pathCompiler) splitPipe(path string) (left, right *Path, err error) {
+	lpath, rpath, ok := splitPossiblePipe(path)
+	if !ok {
+		left, err = cp.path(path)
+		return left, nil, err
+	}
+	if left, err = cp.path(lpath); err != nil {
+		return nil, nil, err
+	}
+	if right, err = cp.path(rpath); err != nil {
+		return nil, nil, err
+	}
+	return left, right, nil
+}
+
+// offsetOf returns the position of sub in s, where sub is expected to be a
+// substring of s. Zero is returned if sub is not inside s.
+func offsetOf(s, sub string) int {
+	shdr := (*stringHeader)(unsafe.Pointer(&s))
+	bhdr := (*stringHeader)(unsafe.Pointer(&sub))
+	off := int(uintptr(bhdr.data) - uintptr(shdr.data))
+	if off < 0 || off > len(s) {
+		return 0
+	}
+	return off
+}
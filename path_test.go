@@ -0,0 +1,142 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// samplePaths covers the path syntax, it is searched in sampleJSONs to compare
+// different implementations of searching with Get.
+var samplePaths = []string{
+	"", "age", "name", "name.first", "name.last", "name.here", "noop.what is a wren?",
+	"happy", "immortal", "items", "items.3.tags.1", "items.3.points.1.0", "items.#",
+	"items.100", "arr.3.hello", "vals.3.sadf", "created", "loggy.programmers.1.email",
+	`loggy.programmers.#[tag="good"].firstName`, `loggy.programmers.#[tag="good"]#.firstName`,
+	"loggy.programmers.#.firstName", "loggy.programmers.#.age", "loggy.programmers.#",
+	`loggy.programmers.#(age>100).firstName`, "loggy.prog*.0.last*", "lastly.end\\.\\.\\.ing",
+	"lastly.yay", "l*.y?y", "children", "children.1", "children.#", "child*.2", "c?ildren.0",
+	"fav\\.movie", "friends.#.first", "friends.1.last", `friends.#(last=="Murphy").first`,
+	`friends.#(last=="Murphy")#.first`, "friends.#(age>45)#.last", `friends.#(first%"D*").last`,
+	`friends.#(first!%"D*").last`, `friends.#(nets.#(=="fb"))#.first`, "friends.#(age>45)#|#",
+	"friends.#.first|@reverse", "friends|@reverse|0.first", "@reverse", "children|@reverse",
+	"children.@reverse", "children.@reverse.0", "name.@pretty", `@pretty:{"sortKeys":true}`,
+	"@this", "@this.name", "!true", "!\"static\"", "![1,2]|0", `{name.first,age,"the_murphys":friends.#(last="Murphy")#.first}`,
+	"[name.first,age,children.0]", "[name,age].0.first", "{age,children}|children.1",
+	"friends.#.{first,age}", "friends.0.[first,last]", "..#", "..0", "..#.name",
+	"users.#(name=~\"x\")", "#", "0", "0.a", "friends.#[", "friends.#(first=\"Dale\"",
+	"loggy.programmers.#(tag=good)#.email|@reverse|0", "items.#.#", "items.#(tags)#",
+	"items.#(tags.#>2).points", "vals.#(=~1)", "arr.#(==\"3\")", "arr.#(!=5)#",
+	"widget.window.name", "widget.image.hOffset", "widget.text.onMouseUp", "loggy.programmers.#(%\"B*\")",
+	"[x]*", "[)*", "[,.}*", "[name]first", "{age}x.y",
+}
+
+var sampleJSONs = []string{basicJSON, readmeJSON, complicatedJSON, exampleJSON, manyJSON,
+	`[{"a":1},{"a":2}]`, "{\"name\":\"a\"}\n{\"name\":\"b\"}\n", `[1,2,3]`, `"str"`}
+
+func TestCompileMatchesGet(t *testing.T) {
+	for _, json := range sampleJSONs {
+		for _, path := range samplePaths {
+			p, err := Compile(path)
+			if err != nil {
+				continue
+			}
+			if p.String() != path {
+				t.Fatalf("expected %q, got %q", path, p.String())
+			}
+			exp, got := Get(json, path), p.Get(json)
+			if !reflect.DeepEqual(exp, got) {
+				t.Fatalf("path %q: expected %#v, got %#v", path, exp, got)
+			}
+			got = p.GetBytes([]byte(json))
+			if !reflect.DeepEqual(exp, got) {
+				t.Fatalf("path %q: expected %#v, got %#v", path, exp, got)
+			}
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, tt := range []struct {
+		path   string
+		offset int
+	}{
+		{`friends.#(last=="Murphy"`, 8},
+		{`friends.#[last=="Murphy"`, 8},
+		{`friends.#(last=="Murphy)`, 16},
+		{`[name,age`, 0},
+		{`name|{first,last`, 5},
+		{`friends.#(nets.#(=="fb").first`, 8},
+		{`a.#(b.#(c==1).d`, 2},
+		{`@reverse|[a,b`, 9},
+		{`friends.#(age>40 &&)#`, 19},
+		{`friends.#((age>40) x)#`, 19},
+		{`friends.#(!)#`, 11},
+		{`friends.#(first=~"(")#.first`, 17},
+		{`friends.#(first!~"(")`, 17},
+		{`friends.#(age>1 && first=~ "[")#`, 27},
+		{`friends.#(last contains )`, 24},
+		{`friends.#(age>40 && last in)#`, 27},
+	} {
+		_, err := Compile(tt.path)
+		var perr *PathSyntaxError
+		if !errors.As(err, &perr) {
+			t.Fatalf("path %q: expected a PathSyntaxError, got %v", tt.path, err)
+		}
+		if perr.Offset != tt.offset || perr.Path != tt.path {
+			t.Fatalf("path %q: expected offset %d, got %d", tt.path, tt.offset, perr.Offset)
+		}
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected a panic")
+			}
+		}()
+		MustCompile("[a,b")
+	}()
+	assert(t, MustCompile("name.last").Get(readmeJSON).String() == "Anderson")
+}
+
+func TestCompiledIndexes(t *testing.T) {
+	p := MustCompile(`friends.#(last="Murphy")#`)
+	res := p.Get(readmeJSON)
+	assert(t, reflect.DeepEqual(res.Indexes, Get(readmeJSON, p.String()).Indexes))
+	for i, v := range res.Array() {
+		assert(t, readmeJSON[res.Indexes[i]:res.Indexes[i]+len(v.Raw)] == v.Raw)
+	}
+	sub := Get(readmeJSON, "friends")
+	assert(t, reflect.DeepEqual(sub.getPath(p), sub.Get(p.String())))
+}
+
+func BenchmarkCompiledPath(b *testing.B) {
+	path := `loggy.programmers.#(tag="good")#.firstName`
+	b.Run("Get", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Get(basicJSON, path)
+		}
+	})
+	b.Run("Path", func(b *testing.B) {
+		p := MustCompile(path)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			p.Get(basicJSON)
+		}
+	})
+}
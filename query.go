@@ -0,0 +1,551 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/gjson/internal/caching"
+)
+
+// queryInvalid is the op of a compound query which failed to parse, and
+// which never matches.
+const queryInvalid = 'x'
+
+// queryExpr is a compound array query, such as
+//
+//	friends.#(age>40 && (last=="Murphy" || !nets))
+//
+// which joins the comparisons of single queries with "&&", "||", "!" and
+// parentheses.
+type queryExpr struct {
+	// op is '&', '|' or '!' for the logical operators, zero for a
+	// comparison, and queryInvalid for an invalid query.
+	op          byte
+	left, right *queryExpr
+
+	// raw is the text of the comparison, which is split into path, cmp
+	// and value like a single query.
+	raw   string
+	path  string
+	cmp   string
+	value string
+	ref   *queryRef
+	re    *regexp.Regexp
+	query *Path // the compiled path, if any
+}
+
+// queryRef is a reference to another value on the right of a comparison,
+// either of the array element, such as @.budget, or of the json being
+// searched, such as $.limits.max.
+type queryRef struct {
+	root  bool
+	path  string
+	query *Path // the compiled path, if any
+}
+
+// parseQueryRef parses the value of a query, before its quotes are stripped,
+// as a reference. It returns nil if the value is not a reference.
+func parseQueryRef(value string) *queryRef {
+	if len(value) < 3 || (value[0] != '@' && value[0] != '$') || value[1] != '.' {
+		return nil
+	}
+	return &queryRef{root: value[0] == '$', path: value[2:]}
+}
+
+// resolve returns the referenced value for the array element elem of the
+// json root.
+func (r *queryRef) resolve(elem Result, root string, opts *Options) Result {
+	json := root
+	if !r.root {
+		if elem.Type != JSON {
+			return Result{}
+		}
+		json = elem.Raw
+	}
+	if r.query != nil {
+		return r.query.Get(json)
+	}
+	return getWith(json, r.path, opts)
+}
+
+// refMatches compares value to the referenced value ref. A missing or null
+// reference never matches.
+func refMatches(op string, ref, value Result, opts *Options) bool {
+	if !ref.Exists() || ref.Type == Null {
+		return false
+	}
+	return compareQuery(op, ref.String(), value, opts)
+}
+
+// isCompoundQuery tells if the query between the brackets of an array query
+// uses the logical operators, rather than being a single comparison.
+func isCompoundQuery(q string) bool {
+	q = trim(q)
+	if len(q) == 0 {
+		return false
+	}
+	if q[0] == '(' || (q[0] == '!' && !isNotOp(q)) {
+		return true
+	}
+	return scanComparison(q, 0) < len(q)
+}
+
+// isNotOp tells if q starts with the "!=" or "!%" operator, rather than
+// with a negation.
+func isNotOp(q string) bool {
+	return len(q) > 1 && (q[1] == '=' || q[1] == '%')
+}
+
+// scanComparison returns the end of the comparison starting at q[i], which
+// is the first "&&", "||" or unbalanced closing bracket outside strings.
+func scanComparison(q string, i int) int {
+	depth := 0
+	for ; i < len(q); i++ {
+		switch q[i] {
+		case '\\':
+			i++
+		case '"':
+			for i++; i < len(q) && q[i] != '"'; i++ {
+				if q[i] == '\\' {
+					i++
+				}
+			}
+		case '(', '[':
+			depth++
+		case ')', ']':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case '&', '|':
+			if depth == 0 && i+1 < len(q) && q[i+1] == q[i] {
+				return i
+			}
+		}
+	}
+	return len(q)
+}
+
+// splitComparison splits a comparison of a compound query into its path, op
+// and value, the same as parseQuery does for a single query.
+func splitComparison(q string) (path, op, value string, vesc bool) {
+	if ws, we := findWordOp(q); ws >= 0 {
+		path, op, value = trim(q[:ws]), q[ws:we], trim(q[we:])
+		return path, op, value, strings.IndexByte(value, '\\') >= 0
+	}
+	j := -1 // start of value part
+	depth := 0
+	for i := 0; i < len(q); i++ {
+		if depth == 0 && j < 0 && strings.IndexByte("!=<>%", q[i]) >= 0 {
+			j = i
+			continue
+		}
+		switch q[i] {
+		case '\\':
+			i++
+		case '"':
+			for i++; i < len(q) && q[i] != '"'; i++ {
+				if q[i] == '\\' {
+					vesc = true
+					i++
+				}
+			}
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		}
+	}
+	if j < 0 {
+		if k := findTypeOp(q); k >= 0 {
+			return trim(q[:k]), ":", trim(q[k+1:]), vesc
+		}
+		return trim(q), "", "", vesc
+	}
+	op, value = parseQueryOp(trim(q[j:]))
+	return trim(q[:j]), op, value, vesc
+}
+
+// wordOps are the query operators made of letters, which are separated from
+// the path and the value by spaces.
+var wordOps = []string{"in", "!in", "contains", "!contains", "startsWith", "endsWith"}
+
+// findWordOp returns the span of the word operator of the comparison q, or
+// -1 if the comparison has none. A word operator is preceded by a space,
+// unless there is no path, and is followed by the value. A word operator
+// which ends a comparison with a path, such as "tags contains", misses its
+// value.
+func findWordOp(q string) (start, end int) {
+	depth := 0
+	for i := 0; i < len(q); i++ {
+		if depth == 0 && (i == 0 || q[i-1] <= ' ') {
+			for _, op := range wordOps {
+				k := i + len(op)
+				if !strings.HasPrefix(q[i:], op) {
+					continue
+				}
+				v := trim(q[k:])
+				if v == "" && trim(q[:i]) != "" {
+					return i, k
+				}
+				if v != "" && (q[k] <= ' ' || q[k] == '[' || q[k] == '"') &&
+					strings.IndexByte("!=<>%", v[0]) < 0 {
+					return i, k
+				}
+			}
+		}
+		switch q[i] {
+		case '\\':
+			i++
+		case '"':
+			for i++; i < len(q) && q[i] != '"'; i++ {
+				if q[i] == '\\' {
+					i++
+				}
+			}
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case '!', '=', '<', '>', '%':
+			if depth == 0 {
+				// a symbol operator comes first
+				return -1, -1
+			}
+		}
+	}
+	return -1, -1
+}
+
+// wordMatches compares value to the value rpv of a word operator.
+func wordMatches(op, rpv string, value Result, opts *Options) bool {
+	switch op {
+	case "in":
+		return inList(rpv, value, opts)
+	case "!in":
+		return !inList(rpv, value, opts)
+	case "contains":
+		return contains(rpv, value, opts)
+	case "!contains":
+		return !contains(rpv, value, opts)
+	}
+	if value.IsArray() {
+		arr := value.Array()
+		if len(arr) == 0 {
+			return false
+		}
+		if op == "startsWith" {
+			return compareQuery("=", rpv, arr[0], opts)
+		}
+		return compareQuery("=", rpv, arr[len(arr)-1], opts)
+	}
+	if value.Type != String {
+		return false
+	}
+	if op == "startsWith" {
+		return strings.HasPrefix(value.Str, rpv)
+	}
+	return strings.HasSuffix(value.Str, rpv)
+}
+
+// inList tells if value equals a value of the json array list.
+func inList(list string, value Result, opts *Options) bool {
+	match := false
+	Parse(list).ForEach(func(_, v Result) bool {
+		match = refMatches("=", v, value, opts)
+		return !match
+	})
+	return match
+}
+
+// contains tells if the array value has an element equal to rpv, or if the
+// string value contains rpv.
+func contains(rpv string, value Result, opts *Options) bool {
+	if value.IsArray() {
+		match := false
+		value.ForEach(func(_, v Result) bool {
+			match = compareQuery("=", rpv, v, opts)
+			return !match
+		})
+		return match
+	}
+	return value.Type == String && strings.Contains(value.Str, rpv)
+}
+
+// findTypeOp returns the position of the ":" operator of the comparison q,
+// which tests the type of the value, such as id:number, or -1 if the
+// comparison has none. The colon of a modifier argument, or an escaped
+// colon, is part of the path.
+func findTypeOp(q string) int {
+	end := len(q)
+	for end > 0 && q[end-1] <= ' ' {
+		end--
+	}
+	i := strings.LastIndexByte(q[:end], ':')
+	if i < 0 || !isQueryType(trim(q[i+1:end])) || (i > 0 && q[i-1] == '\\') {
+		return -1
+	}
+	start := strings.LastIndexAny(q[:i], ".|") + 1
+	if c := trim(q[start:i]); len(c) > 0 && c[0] == '@' {
+		return -1
+	}
+	return i
+}
+
+// isQueryType tells if typ is a type name of the ":" operator.
+func isQueryType(typ string) bool {
+	switch typ {
+	case "string", "number", "bool", "null", "object", "array":
+		return true
+	}
+	return false
+}
+
+// typeMatches tells if value is of the type typ of the ":" operator.
+func typeMatches(typ string, value Result) bool {
+	switch typ {
+	case "string":
+		return value.Type == String
+	case "number":
+		return value.Type == Number
+	case "bool":
+		return value.Type == True || value.Type == False
+	case "null":
+		return value.Type == Null
+	case "object":
+		return value.IsObject()
+	case "array":
+		return value.IsArray()
+	}
+	return false
+}
+
+// parseQueryExpr parses a compound query, which is the part between the
+// brackets of an array query. An invalid query never matches, and is
+// reported by Compile.
+func parseQueryExpr(q string, opts *Options) *queryExpr {
+	p := queryParser{src: q, opts: opts}
+	if x := p.parse(); x != nil {
+		return x
+	}
+	return &queryExpr{op: queryInvalid}
+}
+
+// queryParser parses a compound query by recursive descent, where "!" binds
+// tighter than "&&", which binds tighter than "||".
+type queryParser struct {
+	src  string
+	i    int
+	opts *Options
+	msg  string // the first error, if any
+	pos  int    // the offset of the error
+}
+
+func (p *queryParser) fail(pos int, msg string) *queryExpr {
+	if p.msg == "" {
+		p.msg, p.pos = msg, pos
+	}
+	return nil
+}
+
+func (p *queryParser) space() {
+	for p.i < len(p.src) && p.src[p.i] <= ' ' {
+		p.i++
+	}
+}
+
+// operator consumes the logical operator made of two c characters.
+func (p *queryParser) operator(c byte) bool {
+	p.space()
+	if p.i+1 < len(p.src) && p.src[p.i] == c && p.src[p.i+1] == c {
+		p.i += 2
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) parse() *queryExpr {
+	x := p.or()
+	if x == nil {
+		return nil
+	}
+	if p.space(); p.i < len(p.src) {
+		return p.fail(p.i, "unexpected "+strconv.QuoteRune(rune(p.src[p.i]))+" in query")
+	}
+	return x
+}
+
+func (p *queryParser) or() *queryExpr {
+	x := p.and()
+	for x != nil && p.operator('|') {
+		y := p.and()
+		if y == nil {
+			return nil
+		}
+		x = &queryExpr{op: '|', left: x, right: y}
+	}
+	return x
+}
+
+func (p *queryParser) and() *queryExpr {
+	x := p.unary()
+	for x != nil && p.operator('&') {
+		y := p.unary()
+		if y == nil {
+			return nil
+		}
+		x = &queryExpr{op: '&', left: x, right: y}
+	}
+	return x
+}
+
+func (p *queryParser) unary() *queryExpr {
+	p.space()
+	if p.i < len(p.src) {
+		switch p.src[p.i] {
+		case '!':
+			if isNotOp(p.src[p.i:]) {
+				break
+			}
+			p.i++
+			x := p.unary()
+			if x == nil {
+				return nil
+			}
+			return &queryExpr{op: '!', left: x}
+		case '(':
+			open := p.i
+			p.i++
+			x := p.or()
+			if x == nil {
+				return nil
+			}
+			if p.space(); p.i == len(p.src) || p.src[p.i] != ')' {
+				return p.fail(open, "unclosed '(' in query")
+			}
+			p.i++
+			return x
+		}
+	}
+	return p.comparison()
+}
+
+func (p *queryParser) comparison() *queryExpr {
+	start := p.i
+	p.i = scanComparison(p.src, p.i)
+	raw := trim(p.src[start:p.i])
+	if raw == "" {
+		return p.fail(start, "missing operand in query")
+	}
+	x := &queryExpr{raw: raw}
+	var vesc bool
+	x.path, x.cmp, x.value, vesc = splitComparison(raw)
+	x.ref = parseQueryRef(x.value)
+	x.value = queryValue(x.value, vesc, p.opts)
+	x.re = queryRegexp(x.cmp, x.value)
+	return x
+}
+
+// matches tells if the array element v of the json root matches the query.
+func (x *queryExpr) matches(v Result, root string, opts *Options) bool {
+	switch x.op {
+	case '&':
+		return x.left.matches(v, root, opts) && x.right.matches(v, root, opts)
+	case '|':
+		return x.left.matches(v, root, opts) || x.right.matches(v, root, opts)
+	case '!':
+		return !x.left.matches(v, root, opts)
+	case 0:
+		var res Result
+		switch {
+		case x.path == "" && x.cmp == ":":
+			// the element itself, such as #(:object)
+			res = v
+		case v.Type == JSON:
+			if x.query != nil {
+				res = v.getPath(x.query)
+			} else {
+				res = v.getWith(x.path, opts)
+			}
+		case x.path != "":
+			return false
+		default:
+			res = v
+		}
+		switch {
+		case x.re != nil:
+			return regexpMatches(x.cmp, x.re, res)
+		case x.ref != nil:
+			return refMatches(x.cmp, x.ref.resolve(v, root, opts), res, opts)
+		}
+		return queryMatches(x.cmp, x.value, res, opts)
+	}
+	return false
+}
+
+// regexpCacheSize is the capacity of the cache of the regexps of queries,
+// which saves compiling them again when a path is not compiled.
+const regexpCacheSize = 1024
+
+var regexpCache = caching.NewBounded(regexpCacheSize, caching.CLOCK)
+
+// isRegexpValue tells if the value following "=~" or "!~" is a string,
+// thus the op is a regexp match. Otherwise "=~" is followed by the value
+// of a "=" op, such as ~true.
+func isRegexpValue(value string) bool {
+	value = trim(value)
+	return len(value) > 0 && value[0] == '"'
+}
+
+// queryRegexp returns the compiled regexp of the value of the =~ and !~ ops,
+// or nil if the op is not a regexp match or the regexp is invalid, which
+// never matches with Get and is reported by Compile.
+func queryRegexp(op, value string) *regexp.Regexp {
+	if op != "=~" && op != "!~" {
+		return nil
+	}
+	if v := regexpCache.GetByStr(value); v != nil {
+		return v.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(value)
+	if err != nil {
+		// the invalid regexps are cached as nil too, as the interface
+		// holding a nil *regexp.Regexp is not a nil interface
+		re = nil
+	}
+	regexpCache.SetByStr(value, re)
+	return re
+}
+
+// regexpMatches tells if the value matches the regexp re of the =~ op, or
+// does not match it for the !~ op. Only strings, numbers and booleans are
+// matched, numbers and booleans by their raw text.
+func regexpMatches(op string, re *regexp.Regexp, value Result) bool {
+	var s string
+	switch value.Type {
+	case String:
+		s = value.Str
+	case Number, True, False:
+		s = value.Raw
+	default:
+		return false
+	}
+	return re.MatchString(s) == (op == "=~")
+}
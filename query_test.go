@@ -0,0 +1,229 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import "testing"
+
+func TestCompoundQueries(t *testing.T) {
+	for _, c := range []struct {
+		path, expect string
+	}{
+		{`friends.#(age>40 && last=="Murphy")#.first`, `["Dale","Jane"]`},
+		{`friends.#(age>45&&last=="Murphy").first`, `Jane`},
+		{`friends.#(first=="Roger" || age<45)#.first`, `["Dale","Roger"]`},
+		{`friends.#(!(last=="Murphy"))#.first`, `["Roger"]`},
+		{`friends.#(!last=="Murphy")#.first`, `["Roger"]`},
+		{`friends.#(last!="Murphy" || age>45 && first=="Jane")#.first`, `["Roger","Jane"]`},
+		{`friends.#((last!="Murphy" || age>45) && first=="Jane")#.first`, `["Jane"]`},
+		{`friends.#(nets.#(=="fb") && !(age>60))#.first`, `["Dale"]`},
+		{`friends.#(first%"R*" || first%"J*")#.first`, `["Roger","Jane"]`},
+		{`friends.#(last=="a&&b" || last=="x||y")#.first`, `[]`},
+		{`friends.#(!nets.#(=="ig")).first`, `Roger`},
+		{`children.#(=="Sara" || =="Jack")#`, `["Sara","Jack"]`},
+		{`children.#(!="Sara" && !%"J*")`, `Alex`},
+		// single comparisons are unchanged
+		{`friends.#(last=="Murphy")#.first`, `["Dale","Jane"]`},
+		{`friends.#(age!=44)#.first`, `["Roger","Jane"]`},
+	} {
+		if res := Get(readmeJSON, c.path).String(); res != c.expect {
+			t.Fatalf("%q: expected %q, got %q", c.path, c.expect, res)
+		}
+		p, err := Compile(c.path)
+		if err != nil {
+			t.Fatalf("%q: %v", c.path, err)
+		}
+		if res := p.Get(readmeJSON).String(); res != c.expect {
+			t.Fatalf("compiled %q: expected %q, got %q", c.path, c.expect, res)
+		}
+	}
+}
+
+func TestQueryReferences(t *testing.T) {
+	json := `{
+		"limits": {"max": 50, "currency": "EUR"},
+		"orders": [
+			{"id": 1, "budget": 20, "currency": "EUR", "items": [{"price": 10}, {"price": 30}]},
+			{"id": 2, "budget": 40, "currency": "USD", "items": [{"price": 35}, {"price": 60}]},
+			{"id": 3, "budget": null, "currency": "EUR", "items": []}
+		]
+	}`
+	for _, c := range []struct {
+		path, expect string
+	}{
+		{`orders.#(budget<$.limits.max)#.id`, `[1,2]`},
+		{`orders.#(currency==$.limits.currency)#.id`, `[1,3]`},
+		{`orders.#(currency!=$.limits.currency)#.id`, `[2]`},
+		{`orders.#(items.#(price>30) && budget>30)#.id`, `[2]`},
+		{`orders.#(items.0.price<@.budget)#.id`, `[1,2]`},
+		{`orders.#(items.1.price>=@.budget && currency==$.limits.currency)#.id`, `[1]`},
+		{`orders.1.items.#(price<$.orders.1.budget)#.price`, `[35]`},
+		{`orders.#(budget==@.missing)#.id`, `[]`},
+		{`orders.#(id!=@.budget)#.id`, `[1,2]`},
+		{`orders.#(currency=="$.limits.currency")#.id`, `[]`},
+	} {
+		if res := Get(json, c.path).String(); res != c.expect {
+			t.Fatalf("%q: expected %q, got %q", c.path, c.expect, res)
+		}
+		p, err := Compile(c.path)
+		if err != nil {
+			t.Fatalf("%q: %v", c.path, err)
+		}
+		if res := p.Get(json).String(); res != c.expect {
+			t.Fatalf("compiled %q: expected %q, got %q", c.path, c.expect, res)
+		}
+	}
+}
+
+func TestRegexpQueries(t *testing.T) {
+	json := `{"friends": [
+		{"first": "Dale", "email": "dale@corp.com", "id": 101, "active": true},
+		{"first": "Roger", "email": "roger@corpXcom", "id": 202, "active": false},
+		{"first": "Jane", "email": "jane@home.org", "id": 103}
+	]}`
+	for _, c := range []struct {
+		path, expect string
+	}{
+		{`friends.#(email=~"^.*@corp\\.com$")#.first`, `["Dale"]`},
+		{`friends.#(email!~"^.*@corp\\.com$")#.first`, `["Roger","Jane"]`},
+		{`friends.#(email =~ "@corp").first`, `Dale`},
+		{`friends.#(first=~"^(Dale|Jane)$")#.first`, `["Dale","Jane"]`},
+		{`friends.#(first=~"^[A-Z][a-z]{3}$")#.first`, `["Dale","Jane"]`},
+		{`friends.#(id=~"^1")#.first`, `["Dale","Jane"]`},
+		{`friends.#(active=~"^t")#.first`, `["Dale"]`},
+		{`friends.#(missing!~"x")#.first`, `[]`},
+		{`friends.#(first=~"^D" || email=~"\\.org$")#.first`, `["Dale","Jane"]`},
+		// =~ followed by a bool value is an equality
+		{`friends.#(active=~true)#.first`, `["Dale"]`},
+	} {
+		if res := Get(json, c.path).String(); res != c.expect {
+			t.Fatalf("%q: expected %q, got %q", c.path, c.expect, res)
+		}
+		p, err := Compile(c.path)
+		if err != nil {
+			t.Fatalf("%q: %v", c.path, err)
+		}
+		if res := p.Get(json).String(); res != c.expect {
+			t.Fatalf("compiled %q: expected %q, got %q", c.path, c.expect, res)
+		}
+	}
+	re := queryRegexp("=~", "^a+$")
+	assert(t, re != nil && queryRegexp("!~", "^a+$") == re)
+	assert(t, queryRegexp("=", "^a+$") == nil)
+	// invalid regexps never match with Get, and are reported by Compile
+	assert(t, queryRegexp("=~", "(") == nil && queryRegexp("=~", "(") == nil)
+	assert(t, Get(json, `friends.#(first=~"(")#.first`).String() == `[]`)
+	assert(t, Get(json, `friends.#(first!~"(")#.first`).String() == `[]`)
+}
+
+func TestWordQueries(t *testing.T) {
+	json := `{
+		"allowed": ["open", "pending"],
+		"tickets": [
+			{"id": 1, "status": "open", "tags": ["bug", "ui"], "title": "Crash on start", "prio": 2},
+			{"id": 2, "status": "closed", "tags": ["feature"], "title": "Dark mode", "prio": 3},
+			{"id": 3, "status": "pending", "tags": [], "title": "Slow start", "prio": 1}
+		]
+	}`
+	for _, c := range []struct {
+		path, expect string
+	}{
+		{`tickets.#(status in ["open","pending"])#.id`, `[1,3]`},
+		{`tickets.#(status !in ["open","pending"])#.id`, `[2]`},
+		{`tickets.#(prio in [1,2])#.id`, `[1,3]`},
+		{`tickets.#(status in $.allowed)#.id`, `[1,3]`},
+		{`tickets.#(tags contains "bug")#.id`, `[1]`},
+		{`tickets.#(tags !contains "bug")#.id`, `[2,3]`},
+		{`tickets.#(title contains "start")#.id`, `[1,3]`},
+		{`tickets.#(title startsWith "Slow").id`, `3`},
+		{`tickets.#(title endsWith "mode").id`, `2`},
+		{`tickets.#(tags startsWith "bug")#.id`, `[1]`},
+		{`tickets.#(tags endsWith "feature")#.id`, `[2]`},
+		{`tickets.#(status in ["open"] || tags contains "feature")#.id`, `[1,2]`},
+		{`tickets.#(tags.#(startsWith"f"))#.id`, `[2]`},
+		{`allowed.#(in ["pending","x"])#`, `["pending"]`},
+		{`tickets.#(missing !in ["x"])#.id`, `[]`},
+	} {
+		if res := Get(json, c.path).String(); res != c.expect {
+			t.Fatalf("%q: expected %q, got %q", c.path, c.expect, res)
+		}
+		p, err := Compile(c.path)
+		if err != nil {
+			t.Fatalf("%q: %v", c.path, err)
+		}
+		if res := p.Get(json).String(); res != c.expect {
+			t.Fatalf("compiled %q: expected %q, got %q", c.path, c.expect, res)
+		}
+	}
+	// a list which is not an array never matches
+	assert(t, Get(json, `tickets.#(status in "open")#.id`).String() == `[]`)
+	// word operators need the spaces around them, otherwise they are paths
+	assert(t, Get(`[{"in":1},{"in":2}]`, `#(in==2).in`).Int() == 2)
+	assert(t, Get(`[{"a":"in x"}]`, `#(a=="in x").a`).String() == "in x")
+}
+
+func TestTypeQueries(t *testing.T) {
+	json := `[
+		{"id": 1, "meta": {"a": 1}, "x": null, "ok": true},
+		{"id": "2", "meta": [1], "x": 0, "ok": "yes"},
+		{"id": 3.5, "meta": null, "ok": false},
+		{"name": "no id"}
+	]`
+	for _, c := range []struct {
+		path, expect string
+	}{
+		{`#(id:number)#.id`, `[1,3.5]`},
+		{`#(id:string)#.id`, `["2"]`},
+		{`#(meta:object)#.id`, `[1]`},
+		{`#(meta:array)#.id`, `["2"]`},
+		{`#(meta : null)#.id`, `[3.5]`},
+		{`#(x:null)#.id`, `[1]`},
+		{`#(ok:bool)#.id`, `[1,3.5]`},
+		{`#(!(id:number))#`, `[{"id": "2", "meta": [1], "x": 0, "ok": "yes"},{"name": "no id"}]`},
+		{`#(id && !(id:number))#.id`, `["2"]`},
+		{`#(id:number && meta:object)#.id`, `[1]`},
+		{`#(meta.0:number).id`, `2`},
+		{`#.meta.#(:number)`, `[1]`},
+		{`#(:object)#.id`, `[1,"2",3.5]`},
+	} {
+		if res := Get(json, c.path).String(); res != c.expect {
+			t.Fatalf("%q: expected %q, got %q", c.path, c.expect, res)
+		}
+		p, err := Compile(c.path)
+		if err != nil {
+			t.Fatalf("%q: %v", c.path, err)
+		}
+		if res := p.Get(json).String(); res != c.expect {
+			t.Fatalf("compiled %q: expected %q, got %q", c.path, c.expect, res)
+		}
+	}
+	// unknown types and escaped colons are part of the path
+	assert(t, Get(`[{"a:b":1}]`, `#(a\:b).a\:b`).Int() == 1)
+	assert(t, Get(`[{"a:number":"x"}]`, `#(a\:number)#.a\:number`).String() == `["x"]`)
+}
+
+func TestEmptyQueries(t *testing.T) {
+	// only the type predicates test the element itself, the other queries
+	// without a path never match the objects and arrays
+	assert(t, Get(readmeJSON, `friends.#()`).Raw == "")
+	assert(t, Get(readmeJSON, `friends.#()#.first`).Raw == "[]")
+	assert(t, Get(readmeJSON, `friends.#(=="Dale")#`).Raw == "[]")
+	assert(t, Get(`{"n":[1,{"a":1},"x",[2]]}`, `n.#()#`).Raw == `[1,"x"]`)
+	assert(t, Get(`{"n":[1,{"a":1},"x",[2]]}`, `n.#(!=1 || :object)#`).Raw == `[{"a":1},"x"]`)
+	// unclosed queries never match
+	assert(t, Get(readmeJSON, `friends.#(last=="Murphy"`).Raw == "")
+	assert(t, Get(readmeJSON, `friends.#(`).Raw == "")
+}
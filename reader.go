@@ -0,0 +1,515 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/gjson/internal/fast"
+)
+
+// readerBufferSize is the size of the chunks read from a reader
+const readerBufferSize = 32 * 1024
+
+// GetReader searches the json read from r for the multiple paths, like
+// GetMany does. The json is scanned incrementally, and only the values to
+// return are buffered. It stops reading once all the paths are found, so r
+// may be left in the middle of the json.
+//
+// Simple paths, which consist of object keys and array indexes only, are
+// resolved while scanning. For other paths, the value of their longest simple
+// prefix is buffered and then searched with Get, such as the array "friends"
+// for the path "friends.#(last=Murphy).first". If any path has no such
+// prefix, like "@this", the whole json is read and searched with GetMany.
+//
+// The Index of a result is its offset in the stream. An error is returned if
+// reading fails, or the json is malformed before all the paths are found,
+// along with the results found so far.
+func GetReader(r io.Reader, path ...string) ([]Result, error) {
+	return getReader(r, path, nil)
+}
+
+func getReader(r io.Reader, path []string, opts *Options) ([]Result, error) {
+	res := make([]Result, len(path))
+	t := newPathTrie(len(path), opts)
+	ends := make([]int, len(path))
+	rests := make([]string, len(path))
+	for i, p := range path {
+		if ends[i] = t.add(p); ends[i] >= 0 {
+			continue
+		}
+		if prefix, rest := fast.ParsePrefix(p); prefix != nil {
+			ends[i], rests[i] = t.add(p[:len(p)-len(rest)-1]), rest
+		}
+		if ends[i] < 0 {
+			// the whole json is needed
+			data, err := io.ReadAll(r)
+			return getMany(string(data), path, opts), err
+		}
+	}
+	var err error
+	if t.nodes[0].pending > 0 {
+		w := streamWalker{t: &t, r: r, buf: make([]byte, 0, readerBufferSize), keep: -1}
+		w.value(0)
+		err = w.failure
+	}
+	for i, n := range ends {
+		res[i] = t.nodes[n].value
+		if rests[i] != "" && res[i].Exists() {
+			res[i] = getRest(res[i], rests[i], opts)
+		}
+	}
+	return res, err
+}
+
+// getRest searches the rest of a path in a value, whose Index is kept
+// relative to the stream.
+func getRest(value Result, rest string, opts *Options) Result {
+	res := getWith(value.Raw, rest, opts)
+	if res.Index > 0 {
+		res.Index += value.Index
+	}
+	for i := range res.Indexes {
+		res.Indexes[i] += value.Index
+	}
+	return res
+}
+
+// streamWalker walks a json read from a reader by the rules of pathTrie.
+// The bytes read are discarded once they are walked, except the ones of the
+// values being captured.
+type streamWalker struct {
+	t   *pathTrie
+	r   io.Reader
+	buf []byte
+	pos int
+	// off is the offset of buf[0] in the stream
+	off int
+	// keep is the offset of the first byte to keep, or -1
+	keep int
+	// line is the number of lines before buf[0], and lineOff is the
+	// offset where the last one of them ends
+	line, lineOff int
+	// rerr is the error of the reader, and failure is the error to return
+	rerr    error
+	failure error
+}
+
+// fill reads more bytes into the buffer, and reports false at the end of the
+// stream.
+func (w *streamWalker) fill() bool {
+	if w.rerr != nil {
+		return false
+	}
+	k := w.pos
+	if w.keep >= 0 && w.keep-w.off < k {
+		k = w.keep - w.off
+	}
+	if k > 0 {
+		if nl := bytes.LastIndexByte(w.buf[:k], '\n'); nl >= 0 {
+			w.line += bytes.Count(w.buf[:nl+1], []byte{'\n'})
+			w.lineOff = w.off + nl + 1
+		}
+		n := copy(w.buf, w.buf[k:])
+		w.buf = w.buf[:n]
+		w.pos -= k
+		w.off += k
+	}
+	if len(w.buf) == cap(w.buf) {
+		buf := make([]byte, len(w.buf), 2*cap(w.buf))
+		copy(buf, w.buf)
+		w.buf = buf
+	}
+	for {
+		n, err := w.r.Read(w.buf[len(w.buf):cap(w.buf)])
+		w.buf = w.buf[:len(w.buf)+n]
+		if err != nil {
+			w.rerr = err
+		}
+		if n > 0 || err != nil {
+			return n > 0
+		}
+	}
+}
+
+// fail stops the walk with a read error, or a syntax error at the current
+// position. It always reports false.
+func (w *streamWalker) fail() bool {
+	if w.failure != nil {
+		return false
+	}
+	if w.rerr != nil && w.rerr != io.EOF {
+		w.failure = w.rerr
+		return false
+	}
+	msg := "unexpected end of input"
+	if w.pos < len(w.buf) {
+		msg = "unexpected character " + quoteChar(w.buf[w.pos:])
+	}
+	line, lineOff := w.line, w.lineOff
+	if nl := bytes.LastIndexByte(w.buf[:w.pos], '\n'); nl >= 0 {
+		line += bytes.Count(w.buf[:nl+1], []byte{'\n'})
+		lineOff = w.off + nl + 1
+	}
+	offset := w.off + w.pos
+	w.failure = &SyntaxError{Offset: offset, Line: line + 1, Column: offset - lineOff + 1, Msg: msg}
+	return false
+}
+
+// space skips the spaces and returns the next byte.
+func (w *streamWalker) space() (byte, bool) {
+	for {
+		for ; w.pos < len(w.buf); w.pos++ {
+			if c := w.buf[w.pos]; !isSpace(c) {
+				return c, true
+			}
+		}
+		if !w.fill() {
+			return 0, w.fail()
+		}
+	}
+}
+
+// next returns the byte at the current position.
+func (w *streamWalker) next() (byte, bool) {
+	if w.pos == len(w.buf) && !w.fill() {
+		return 0, w.fail()
+	}
+	return w.buf[w.pos], true
+}
+
+// string skips a string from its opening quote, and reports if it has escapes.
+func (w *streamWalker) string() (esc, ok bool) {
+	w.pos++
+	for {
+		c, ok := w.next()
+		if !ok {
+			return false, false
+		}
+		w.pos++
+		if c == '"' {
+			return esc, true
+		}
+		if c == '\\' {
+			if _, ok = w.next(); !ok {
+				return false, false
+			}
+			w.pos++
+			esc = true
+		}
+	}
+}
+
+// squash skips an object or an array after its opening bracket.
+func (w *streamWalker) squash() bool {
+	for depth := 1; depth > 0; {
+		c, ok := w.next()
+		if !ok {
+			return false
+		}
+		switch c {
+		case '"':
+			if _, ok = w.string(); !ok {
+				return false
+			}
+			continue
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+		}
+		w.pos++
+	}
+	return true
+}
+
+// scalar skips a number or a literal.
+func (w *streamWalker) scalar() {
+	for {
+		if w.pos == len(w.buf) && !w.fill() {
+			return
+		}
+		if c := w.buf[w.pos]; c <= ' ' || c == ',' || c == ']' || c == '}' {
+			return
+		}
+		w.pos++
+	}
+}
+
+// capture starts keeping the bytes from the current position, and returns
+// the offset of the position and the previous offset to keep.
+func (w *streamWalker) capture() (start, prev int) {
+	start, prev = w.off+w.pos, w.keep
+	if prev < 0 {
+		w.keep = start
+	}
+	return start, prev
+}
+
+// captured returns the bytes kept since the capture started at start,
+// which are valid until the next fill unless prev is not -1.
+func (w *streamWalker) captured(start, prev int) []byte {
+	w.keep = prev
+	return w.buf[start-w.off : w.pos]
+}
+
+// object walks the object members after its opening brace, matched by the
+// node n. It reports true if all the paths of the trie are found.
+func (w *streamWalker) object(n int) bool {
+	for {
+		c, ok := w.space()
+		if !ok {
+			return false
+		}
+		switch c {
+		case '}':
+			w.pos++
+			return false
+		case ',':
+			w.pos++
+			continue
+		case '"':
+		default:
+			return w.fail()
+		}
+		start, prev := w.capture()
+		esc, ok := w.string()
+		if !ok {
+			return false
+		}
+		key := w.captured(start, prev)
+		key = key[1 : len(key)-1]
+		var m int
+		if esc {
+			m = w.t.matchKey(n, unescape(string(key), w.t.opts))
+		} else {
+			m = w.t.matchKey(n, bytesString(key))
+		}
+		if c, ok = w.space(); !ok {
+			return false
+		} else if c != ':' {
+			return w.fail()
+		}
+		w.pos++
+		if w.value(m) || w.failure != nil {
+			return w.failure == nil
+		}
+	}
+}
+
+// array walks the array elements after its opening bracket.
+func (w *streamWalker) array(n int) bool {
+	for h := 0; ; h++ {
+		c, ok := w.space()
+		for ; ok && c == ','; c, ok = w.space() {
+			w.pos++
+		}
+		if !ok {
+			return false
+		}
+		if c == ']' {
+			w.pos++
+			return false
+		}
+		if w.value(w.t.matchIndex(n, h)) || w.failure != nil {
+			return w.failure == nil
+		}
+	}
+}
+
+// value walks the value at the current position, which is matched by the
+// node n, or -1 if it is not matched by any path.
+func (w *streamWalker) value(n int) bool {
+	t := w.t
+	var leaf, walk bool
+	if n >= 0 {
+		leaf = t.nodes[n].leaf && !t.nodes[n].found
+		walk = !leaf || t.nodes[n].pending > 1
+	}
+	c, ok := w.space()
+	if !ok {
+		return false
+	}
+	var start, prev int
+	if leaf {
+		start, prev = w.capture()
+	}
+	switch c {
+	case '{', '[':
+		w.pos++
+		if walk {
+			var done bool
+			if c == '{' {
+				done = w.object(n)
+			} else {
+				done = w.array(n)
+			}
+			if done || w.failure != nil {
+				return done
+			}
+		} else if !w.squash() {
+			return false
+		}
+	case '"':
+		if _, ok = w.string(); !ok {
+			return false
+		}
+	case '}', ']', ',', ':':
+		return w.fail()
+	default:
+		w.scalar()
+	}
+	if !leaf {
+		return false
+	}
+	value := parse(string(w.captured(start, prev)), t.opts)
+	value.Index = start
+	return t.resolve(n, value)
+}
+
+// DefaultMaxLineSize is the default max size of a line read by
+// ForEachLineReader.
+const DefaultMaxLineSize = 1024 * 1024
+
+// ErrLineTooLong is the error of a line larger than the max line size.
+var ErrLineTooLong = errors.New("gjson: line too long")
+
+// LineError describes a malformed line of JSON Lines.
+type LineError struct {
+	// Line is the 1-based line number
+	Line int
+	// Err is a *SyntaxError, whose Offset is in the line, or ErrLineTooLong
+	Err error
+}
+
+func (e *LineError) Error() string {
+	return "gjson: line " + strconv.Itoa(e.Line) + ": " + strings.TrimPrefix(e.Err.Error(), "gjson: ")
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// LineOptions configures ForEachLineReaderWithOptions.
+type LineOptions struct {
+	// MaxLineSize limits the size of a line in bytes, which is also the size
+	// of the buffer. Zero means DefaultMaxLineSize.
+	MaxLineSize int
+	// OnInvalid is called with a malformed line, which is skipped if it
+	// returns true. Otherwise the iteration stops with the error, which is
+	// also the case if OnInvalid is nil.
+	OnInvalid func(err *LineError) bool
+}
+
+// ForEachLineReader iterates through the lines of JSON Lines read from r,
+// like ForEachLine does, with their 1-based line numbers. Empty lines are
+// skipped, and the iteration stops if the iterator returns false.
+//
+// Every line is validated, and a *LineError is returned for the first
+// malformed line, instead of stopping silently. Errors of reading r are
+// returned as they are. See ForEachLineReaderWithOptions to skip malformed
+// lines and to limit the size of a line.
+func ForEachLineReader(r io.Reader, iterator func(line Result, lineNo int) bool) error {
+	return ForEachLineReaderWithOptions(r, iterator, LineOptions{})
+}
+
+// ForEachLineReaderWithOptions iterates through the lines of JSON Lines read
+// from r using the options, see ForEachLineReader.
+func ForEachLineReaderWithOptions(r io.Reader, iterator func(line Result, lineNo int) bool, opts LineOptions) error {
+	s := newLineScanner(r, opts.MaxLineSize)
+	for {
+		line, lineNo, err := s.scan()
+		if err == nil && line != nil {
+			err = validate(line, false)
+			if err == nil {
+				if !iterator(Parse(string(line)), lineNo) {
+					return nil
+				}
+				continue
+			}
+			err = &LineError{Line: lineNo, Err: err}
+		}
+		if err != nil {
+			if lerr, ok := err.(*LineError); !ok || opts.OnInvalid == nil || !opts.OnInvalid(lerr) {
+				return err
+			}
+			continue
+		}
+		return nil
+	}
+}
+
+// lineScanner scans the lines of JSON Lines, using a buffer of the max size
+// of a line.
+type lineScanner struct {
+	br     *bufio.Reader
+	max    int
+	lineNo int
+	eof    bool
+}
+
+func newLineScanner(r io.Reader, max int) *lineScanner {
+	if max <= 0 {
+		max = DefaultMaxLineSize
+	}
+	// room for the line break
+	return &lineScanner{br: bufio.NewReaderSize(r, max+2), max: max}
+}
+
+// scan returns the next line which is not blank, without the spaces after it.
+// The line is valid until the next scan, and it is nil at the end. A line
+// larger than the max size returns a *LineError, and a read error is returned
+// as it is.
+func (s *lineScanner) scan() (line []byte, lineNo int, err error) {
+	for !s.eof {
+		s.lineNo++
+		line, err = s.br.ReadSlice('\n')
+		if err == bufio.ErrBufferFull || len(bytes.TrimRight(line, "\r\n")) > s.max {
+			// skip the rest of the line
+			for err == bufio.ErrBufferFull {
+				_, err = s.br.ReadSlice('\n')
+			}
+			line, err = nil, s.readErr(err)
+			if err == nil {
+				err = &LineError{Line: s.lineNo, Err: ErrLineTooLong}
+			}
+			return line, s.lineNo, err
+		}
+		if err = s.readErr(err); err != nil {
+			return nil, s.lineNo, err
+		}
+		if line = bytes.TrimRight(line, " \t\r\n"); len(bytes.TrimLeft(line, " \t")) > 0 {
+			return line, s.lineNo, nil
+		}
+	}
+	return nil, s.lineNo, nil
+}
+
+// readErr returns the error of reading a line, except io.EOF which ends
+// the scanning after the line.
+func (s *lineScanner) readErr(err error) error {
+	if err == io.EOF {
+		s.eof = true
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,142 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestGetReaderMatchesGetMany(t *testing.T) {
+	var streamed []string
+	for _, path := range samplePaths {
+		if path != "" && path != "@this" && !strings.HasPrefix(path, "..") {
+			streamed = append(streamed, path)
+		}
+	}
+	readers := map[string]func(string) io.Reader{
+		"whole":    func(s string) io.Reader { return strings.NewReader(s) },
+		"one-byte": func(s string) io.Reader { return iotest.OneByteReader(strings.NewReader(s)) },
+		"half":     func(s string) io.Reader { return iotest.HalfReader(strings.NewReader(s)) },
+	}
+	for _, json := range sampleJSONs {
+		for _, paths := range [][]string{streamed, samplePaths, {"name.last"}} {
+			want := GetMany(json, paths...)
+			for name, reader := range readers {
+				got, _ := GetReader(reader(json), paths...)
+				for i := range want {
+					if got[i].Raw != want[i].Raw || got[i].Type != want[i].Type || got[i].Str != want[i].Str ||
+						got[i].Num != want[i].Num || want[i].Index > 0 && got[i].Index != want[i].Index {
+						t.Errorf("GetReader(%s, %q) = %#v, want %#v", name, paths[i], got[i], want[i])
+					}
+				}
+			}
+		}
+	}
+}
+
+// stopReader fails if it is read after the bytes of json.
+type stopReader struct {
+	json string
+}
+
+func (r *stopReader) Read(p []byte) (int, error) {
+	if r.json == "" {
+		return 0, errors.New("read too much")
+	}
+	n := copy(p, r.json)
+	r.json = r.json[n:]
+	return n, nil
+}
+
+func TestGetReaderEarlyExit(t *testing.T) {
+	r := iotest.OneByteReader(&stopReader{`{"a":{"b":[1,"x"]},"c":"d",`})
+	res, err := GetReader(r, "c", "a.b.1", "a.b.#")
+	assert(t, err == nil)
+	assert(t, res[0].String() == "d" && res[0].Index == 23)
+	assert(t, res[1].String() == "x" && res[1].Index == 13)
+	assert(t, res[2].Int() == 2 && res[2].Index == 0)
+
+	// a large value which is skipped is not buffered
+	big := `{"skip":"` + strings.Repeat("x", 10*readerBufferSize) + `","a":1`
+	res, err = GetReader(&stopReader{big}, "a")
+	assert(t, err == nil && res[0].Int() == 1)
+}
+
+func TestGetReaderErrors(t *testing.T) {
+	res, err := GetReader(strings.NewReader("{\"a\":1,\n \"b\" 2}"), "a", "b")
+	se, ok := err.(*SyntaxError)
+	assert(t, ok && se.Offset == 13 && se.Line == 2 && se.Column == 6 && se.Msg == "unexpected character '2'")
+	assert(t, res[0].Int() == 1 && !res[1].Exists())
+
+	_, err = GetReader(strings.NewReader(`{"a":[1,2`), "a")
+	se, ok = err.(*SyntaxError)
+	assert(t, ok && se.Offset == 9 && se.Msg == "unexpected end of input")
+
+	_, err = GetReader(iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader(`{"a":[1,2]}`))), "a")
+	assert(t, err == iotest.ErrTimeout)
+
+	res, err = GetReader(strings.NewReader(`{"a":1}`), "b")
+	assert(t, err == nil && !res[0].Exists())
+	res, err = GetReader(strings.NewReader(`[1,2]`), "@reverse")
+	assert(t, err == nil && res[0].Raw == "[2,1]")
+}
+
+func TestForEachLineReader(t *testing.T) {
+	jsonl := "{\"a\":1}\r\n\n  [2]\n{\"a\":\n\"x\"\n  {\"long\":\"" + strings.Repeat("y", 64) + "\"}\n4"
+	type line struct {
+		no  int
+		raw string
+	}
+	var lines []line
+	iterator := func(res Result, lineNo int) bool {
+		lines = append(lines, line{lineNo, res.Raw})
+		return true
+	}
+
+	err := ForEachLineReader(iotest.OneByteReader(strings.NewReader(jsonl)), iterator)
+	var lerr *LineError
+	var serr *SyntaxError
+	assert(t, errors.As(err, &lerr) && lerr.Line == 4 && errors.As(err, &serr) && serr.Offset == 5)
+	assert(t, err.Error() == "gjson: line 4: unexpected end of input at line 1, column 6 (offset 5)")
+	assert(t, len(lines) == 2 && lines[0] == line{1, `{"a":1}`} && lines[1] == line{3, `[2]`})
+
+	lines = nil
+	var invalid []int
+	err = ForEachLineReaderWithOptions(strings.NewReader(jsonl), iterator, LineOptions{
+		MaxLineSize: 32,
+		OnInvalid: func(err *LineError) bool {
+			invalid = append(invalid, err.Line)
+			return err.Line < 6 || errors.Is(err, ErrLineTooLong)
+		},
+	})
+	assert(t, err == nil)
+	assert(t, len(invalid) == 2 && invalid[0] == 4 && invalid[1] == 6)
+	assert(t, len(lines) == 4 && lines[2] == line{5, `"x"`} && lines[3] == line{7, `4`})
+
+	lines = nil
+	err = ForEachLineReader(strings.NewReader(jsonl), func(res Result, lineNo int) bool {
+		return iterator(res, lineNo) && lineNo < 3
+	})
+	assert(t, err == nil && len(lines) == 2)
+
+	err = ForEachLineReader(iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader(jsonl))), iterator)
+	assert(t, err == iotest.ErrTimeout)
+}
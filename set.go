@@ -0,0 +1,314 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// Set sets a value at the path of json, and returns the new json.
+// The path is a simple path of keys and indexes, like "name.last" or
+// "children.1", where the index -1 appends a value to an array.
+// Missing objects and arrays along the path are created, arrays are padded
+// with nulls up to the index. The value is encoded by encoding/json, except
+// strings which are encoded like AppendJSONString does.
+//
+//	json, err := gjson.Set(`{"name":{"last":"Anderson"}}`, "children.-1", "Sara")
+//	// {"name":{"last":"Anderson"},"children":["Sara"]}
+//
+// The rest of json is copied as it is, and json is not validated. The value
+// set is the one found by Get at the path, thus with duplicate keys, the first
+// one where the rest of the path exists, or else the first one.
+func Set(json, path string, value interface{}) (string, error) {
+	raw, err := marshalValue(value)
+	if err != nil {
+		return json, err
+	}
+	return set(json, path, raw, false)
+}
+
+// SetRaw sets a raw json value at the path of json, see Set.
+// The raw value is not validated.
+func SetRaw(json, path, raw string) (string, error) {
+	return set(json, path, raw, false)
+}
+
+// SetBytes sets a value at the path of json, see Set.
+// A new slice is returned, and json is not modified.
+func SetBytes(json []byte, path string, value interface{}) ([]byte, error) {
+	raw, err := marshalValue(value)
+	if err != nil {
+		return json, err
+	}
+	return setBytes(json, path, raw, false)
+}
+
+// SetRawBytes sets a raw json value at the path of json, see SetRaw.
+// A new slice is returned, and json is not modified.
+func SetRawBytes(json []byte, path string, raw []byte) ([]byte, error) {
+	return setBytes(json, path, string(raw), false)
+}
+
+// Delete deletes the value at the path of json, and returns the new json.
+// The path is a simple path, see Set, where the index -1 deletes the last
+// element of an array. The json is returned as it is if the value is not found.
+// Like Set, the value deleted is the one found by Get at the path.
+func Delete(json, path string) (string, error) {
+	return set(json, path, "", true)
+}
+
+// DeleteBytes deletes the value at the path of json, see Delete.
+// A new slice is returned, and json is not modified.
+func DeleteBytes(json []byte, path string) ([]byte, error) {
+	return setBytes(json, path, "", true)
+}
+
+func setBytes(json []byte, path string, raw string, del bool) ([]byte, error) {
+	res, err := set(bytesString(json), path, raw, del)
+	if err != nil {
+		return json, err
+	}
+	return []byte(res), nil
+}
+
+func marshalValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		return string(AppendJSONString(nil, v)), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	}
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+// checkSetPath checks that path is a simple path, whose components are
+// not empty and have no wildcards and other path syntax.
+func checkSetPath(path string) error {
+	start := 0
+	for i := 0; i <= len(path); i++ {
+		if i == len(path) || path[i] == '.' {
+			if i == start {
+				return &PathSyntaxError{Path: path, Offset: i, Msg: "empty key"}
+			}
+			start = i + 1
+			continue
+		}
+		switch path[i] {
+		case '\\':
+			if i++; i == len(path) {
+				return &PathSyntaxError{Path: path, Offset: i, Msg: "unexpected end of path"}
+			}
+		case '*', '?', '#', '|':
+			return &PathSyntaxError{Path: path, Offset: i, Msg: "unexpected " + strconv.QuoteRune(rune(path[i])) + " in a path to set"}
+		case '@', '!', '[', '{', '(':
+			if i == start {
+				return &PathSyntaxError{Path: path, Offset: i, Msg: "unexpected " + strconv.QuoteRune(rune(path[i])) + " in a path to set"}
+			}
+		}
+	}
+	return nil
+}
+
+// set sets, or deletes, a raw value at the path. Every component of the path
+// is searched in its parent by Get, whose Index locates the child in json.
+func set(json, path, raw string, del bool) (string, error) {
+	if err := checkSetPath(path); err != nil {
+		return json, err
+	}
+	// the span of the parent value
+	start, end := trimSpan(json, 0, len(json))
+	for {
+		rp := parseObjectPath(path, nil)
+		comp := path
+		if rp.more {
+			comp = path[:len(path)-len(rp.path)-1]
+		}
+		parent := json[start:end]
+		if del && comp == "-1" && len(parent) > 0 && parent[0] == '[' {
+			comp = strconv.Itoa(len(Parse(parent).Array()) - 1)
+		}
+		child := setChild(parent, comp, rp)
+		if !child.Exists() {
+			if del {
+				return json, nil
+			}
+			return insertValue(json, start, end, comp, rp.part, rp.path, rp.more, raw)
+		}
+		vstart := start + child.Index
+		vend := vstart + len(child.Raw)
+		if !rp.more {
+			if del {
+				return deleteValue(json, start, end, vstart, vend), nil
+			}
+			return json[:vstart] + raw + json[vend:], nil
+		}
+		start, end, path = vstart, vend, rp.path
+	}
+}
+
+// setChild returns the child comp of parent. Like Get, with duplicate keys
+// the first one where the rest of the path exists is returned, or else the
+// first one.
+func setChild(parent, comp string, rp objectPathResult) Result {
+	child := Get(parent, comp)
+	if !rp.more || !child.Exists() || parent[0] != '{' || Get(child.Raw, rp.path).Exists() {
+		return child
+	}
+	Parse(parent).ForEach(func(key, value Result) bool {
+		if key.Str == rp.part && Get(value.Raw, rp.path).Exists() {
+			child = value
+			return false
+		}
+		return true
+	})
+	return child
+}
+
+// insertValue inserts the missing component comp, and the rest of the path,
+// into the parent value json[start:end], which is replaced if it is neither
+// an object nor an array.
+func insertValue(json string, start, end int, comp, key, rest string, more bool, raw string) (string, error) {
+	if more {
+		raw = newValue(rest, raw)
+	}
+	parent := json[start:end]
+	var elem string
+	switch {
+	case len(parent) > 0 && parent[0] == '{':
+		elem = string(AppendJSONString(nil, key)) + ":" + raw
+	case len(parent) > 0 && parent[0] == '[':
+		idx, err := strconv.Atoi(comp)
+		if err != nil || idx < -1 {
+			return json, errors.New("gjson: cannot set key " + strconv.Quote(key) + " of an array")
+		}
+		if n := len(Parse(parent).Array()); idx > n {
+			raw = repeatNull(idx-n) + raw
+		}
+		elem = raw
+	default:
+		return json[:start] + newValue(comp, raw) + json[end:], nil
+	}
+	// insert after the last member, keeping the spaces before the close
+	i, empty := end-1, true
+	for j := end - 2; j > start; j-- {
+		if !isSpace(json[j]) {
+			i, empty = j+1, false
+			break
+		}
+	}
+	if !empty {
+		elem = "," + elem
+	}
+	return json[:i] + elem + json[i:], nil
+}
+
+// newValue returns the value to set at the path, with objects and arrays
+// created for its components.
+func newValue(path, raw string) string {
+	rp := parseObjectPath(path, nil)
+	comp := path
+	if rp.more {
+		comp = path[:len(path)-len(rp.path)-1]
+		raw = newValue(rp.path, raw)
+	}
+	if comp == "-1" {
+		return "[" + raw + "]"
+	}
+	if idx, ok := parseUint(comp); ok {
+		return "[" + repeatNull(int(idx)) + raw + "]"
+	}
+	return "{" + string(AppendJSONString(nil, rp.part)) + ":" + raw + "}"
+}
+
+func repeatNull(n int) string {
+	buf := make([]byte, 0, n*5)
+	for i := 0; i < n; i++ {
+		buf = append(buf, "null,"...)
+	}
+	return string(buf)
+}
+
+// deleteValue deletes the value json[vstart:vend], and its key, from the
+// parent value json[start:end], with a comma around it.
+func deleteValue(json string, start, end, vstart, vend int) string {
+	if json[start] == '{' {
+		// back to the key of the value
+		i := vstart - 1
+		for ; i > start && json[i] != ':'; i-- {
+		}
+		for i--; i > start && json[i] != '"'; i-- {
+		}
+		for i--; i > start; i-- {
+			if json[i] == '"' && !isEscaped(json, i) {
+				break
+			}
+		}
+		vstart = i
+	}
+	// the comma before or after the value
+	before := vstart - 1
+	for ; before > start && isSpace(json[before]); before-- {
+	}
+	after := vend
+	for ; after < end-1 && isSpace(json[after]); after++ {
+	}
+	switch {
+	case json[before] == ',':
+		return json[:before] + json[vend:]
+	case json[after] == ',':
+		for after++; after < end-1 && isSpace(json[after]); after++ {
+		}
+		return json[:vstart] + json[after:]
+	default:
+		// the only member
+		return json[:start+1] + json[end-1:]
+	}
+}
+
+// isEscaped tells if json[i] is escaped by odd backslashes before it
+func isEscaped(json string, i int) bool {
+	n := 0
+	for i--; i >= 0 && json[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func trimSpan(json string, start, end int) (int, int) {
+	for start < end && isSpace(json[start]) {
+		start++
+	}
+	for end > start && isSpace(json[end-1]) {
+		end--
+	}
+	return start, end
+}
@@ -0,0 +1,133 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		json, path, raw, want string
+	}{
+		{`{"a":1}`, "b", `2`, `{"a":1,"b":2}`},
+		{`{}`, "b", `2`, `{"b":2}`},
+		{``, "a.b.-1", `2`, `{"a":{"b":[2]}}`},
+		{`{"a":[1,2]}`, "a.-1", `3`, `{"a":[1,2,3]}`},
+		{`{"a":[1,2]}`, "a.4", `3`, `{"a":[1,2,null,null,3]}`},
+		{`{"a":[1,2]}`, "a.0", `3`, `{"a":[3,2]}`},
+		{`{"a":[]}`, "a.0", `3`, `{"a":[3]}`},
+		{"{\n  \"a\": 1\n}", "b", `2`, "{\n  \"a\": 1,\"b\":2\n}"},
+		{`{"a":1}`, "a.b", `2`, `{"a":{"b":2}}`},
+		{`{"a":{"b":{}}}`, "a.b.c.1.d", `true`, `{"a":{"b":{"c":[null,{"d":true}]}}}`},
+		{`{"a.b":{"c":1}}`, `a\.b.c`, `2`, `{"a.b":{"c":2}}`},
+		{`{}`, `a\.b`, `2`, `{"a.b":2}`},
+		{` [1] `, "0", `"x"`, ` ["x"] `},
+		{`{"a":1,"a":2}`, "a", `3`, `{"a":3,"a":2}`},
+		{`{"a":{"b":1},"a":{"c":1}}`, "a.c", `2`, `{"a":{"b":1},"a":{"c":2}}`},
+		{`{"a":{"b":1},"a":{"c":1}}`, "a.d", `2`, `{"a":{"b":1,"d":2},"a":{"c":1}}`},
+		{`{"b":"x","a":{"c":[0,{"d":"e"}]}}`, "a.c.1.d", `{"f":null}`, `{"b":"x","a":{"c":[0,{"d":{"f":null}}]}}`},
+	}
+	for _, tt := range tests {
+		got, err := SetRaw(tt.json, tt.path, tt.raw)
+		if err != nil || got != tt.want {
+			t.Errorf("SetRaw(%q, %q, %q) = %q, %v, want %q", tt.json, tt.path, tt.raw, got, err, tt.want)
+		}
+		if v := Get(got, tt.path); tt.path[len(tt.path)-1] != '1' && v.Raw != tt.raw {
+			t.Errorf("Get(%q, %q) = %q, want %q", got, tt.path, v.Raw, tt.raw)
+		}
+		b, err := SetRawBytes([]byte(tt.json), tt.path, []byte(tt.raw))
+		if err != nil || string(b) != tt.want {
+			t.Errorf("SetRawBytes(%q, %q, %q) = %q, %v, want %q", tt.json, tt.path, tt.raw, b, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, `null`},
+		{"a\"<b>", `"a\"\u003cb\u003e"`},
+		{true, `true`},
+		{-12, `-12`},
+		{1.5, `1.5`},
+		{[]string{"x"}, `["x"]`},
+		{map[string]int{"y": 1}, `{"y":1}`},
+	} {
+		got, err := Set(`{"a":0}`, "a", tt.value)
+		if err != nil || got != `{"a":`+tt.want+`}` {
+			t.Errorf("Set(%#v) = %q, %v, want %q", tt.value, got, err, tt.want)
+		}
+		b, err := SetBytes([]byte(`{"a":0}`), "a", tt.value)
+		if err != nil || string(b) != got {
+			t.Errorf("SetBytes(%#v) = %q, %v, want %q", tt.value, b, err, got)
+		}
+	}
+	if _, err := Set(`{}`, "a", func() {}); err == nil {
+		t.Error("expected an error for an unsupported value")
+	}
+}
+
+func TestSetErrors(t *testing.T) {
+	for _, path := range []string{"", ".a", "a.", "a..b", "a.*", "b?", "a.#", "@this", "a|b", `a\`, "a.[b]", "{a}", "!true"} {
+		got, err := SetRaw(`{"a":1}`, path, `2`)
+		if _, ok := err.(*PathSyntaxError); !ok || got != `{"a":1}` {
+			t.Errorf("SetRaw(%q) = %q, %v, want a *PathSyntaxError", path, got, err)
+		}
+		if _, err := Delete(`{"a":1}`, path); err == nil {
+			t.Errorf("Delete(%q) = nil, want an error", path)
+		}
+	}
+	got, err := SetRaw(`{"a":[]}`, "a.x", `2`)
+	assert(t, err != nil && got == `{"a":[]}`)
+	got, err = SetRaw(`{"a@b":1}`, "a@b", `2`)
+	assert(t, err == nil && got == `{"a@b":2}`)
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		json, path, want string
+	}{
+		{`{"a":1,"b":2}`, "a", `{"b":2}`},
+		{`{"a":1,"b":2}`, "b", `{"a":1}`},
+		{`{"a":1}`, "a", `{}`},
+		{"{\n  \"a\": 1,\n  \"b\": 2\n}", "a", "{\n  \"b\": 2\n}"},
+		{"{\n  \"a\": 1,\n  \"b\": 2\n}", "b", "{\n  \"a\": 1\n}"},
+		{"{\n  \"a\": 1\n}", "a", `{}`},
+		{`[1,2,3]`, "1", `[1,3]`},
+		{`[1,2,3]`, "-1", `[1,2]`},
+		{`[1]`, "-1", `[]`},
+		{`[]`, "-1", `[]`},
+		{`{"x\"y":1,"z":[{"k":1}]}`, `x"y`, `{"z":[{"k":1}]}`},
+		{`{"z":[{"k":1,"j":2}]}`, "z.0.k", `{"z":[{"j":2}]}`},
+		{`{"a":1}`, "b", `{"a":1}`},
+		{`{"a":{"b":1},"a":{"c":1}}`, "a.c", `{"a":{"b":1},"a":{}}`},
+		{`{"a":1,"a":2}`, "a", `{"a":2}`},
+		{`{"a":1}`, "a.b", `{"a":1}`},
+		{``, "a", ``},
+	}
+	for _, tt := range tests {
+		got, err := Delete(tt.json, tt.path)
+		if err != nil || got != tt.want {
+			t.Errorf("Delete(%q, %q) = %q, %v, want %q", tt.json, tt.path, got, err, tt.want)
+		}
+		b, err := DeleteBytes([]byte(tt.json), tt.path)
+		if err != nil || string(b) != tt.want {
+			t.Errorf("DeleteBytes(%q, %q) = %q, %v, want %q", tt.json, tt.path, b, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// TimeE returns the time of a RFC3339 string, like Time, and returns a
+// *ConversionError if the value is not a string or a number, or cannot be
+// parsed, whose Err is the *time.ParseError then.
+func (t Result) TimeE() (time.Time, error) {
+	return t.TimeLayoutE(time.RFC3339)
+}
+
+// TimeLayout returns the time of a string, or a number, in the layout of
+// time.Parse, such as time.RFC1123. The zero time is returned if the value
+// cannot be parsed.
+func (t Result) TimeLayout(layout string) time.Time {
+	res, _ := t.TimeLayoutE(layout)
+	return res
+}
+
+// TimeLayoutE returns the time of a string, or a number, in the layout of
+// time.Parse, see TimeE for the errors.
+func (t Result) TimeLayoutE(layout string) (time.Time, error) {
+	if !t.Exists() {
+		return time.Time{}, t.conversionError("time.Time", ErrNotFound)
+	}
+	if t.Type != String && t.Type != Number {
+		return time.Time{}, t.conversionError("time.Time", ErrType)
+	}
+	res, err := time.Parse(layout, t.String())
+	if err != nil {
+		return time.Time{}, t.conversionError("time.Time", err)
+	}
+	return res, nil
+}
+
+// TimeUnix returns the time of a number of units since the Unix epoch, such
+// as time.Second or time.Millisecond, or of a string holding a json number.
+// A fraction of a unit is kept down to a nanosecond. The zero time is
+// returned if the value is not a number or the time is out of range.
+func (t Result) TimeUnix(unit time.Duration) time.Time {
+	res, _ := t.TimeUnixE(unit)
+	return res
+}
+
+// TimeUnixE returns the time of a number of units since the Unix epoch, see
+// TimeUnix, and returns a *ConversionError if the value is not a number or
+// the time is out of range, like IntE does.
+func (t Result) TimeUnixE(unit time.Duration) (time.Time, error) {
+	s, err := t.numberE("time.Time")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && unit > 0 {
+		if unit <= time.Second && time.Second%unit == 0 {
+			per := int64(time.Second / unit)
+			if sec := n / per; sec <= maxUnixSec && sec >= -maxUnixSec {
+				return time.Unix(sec, n%per*int64(unit)), nil
+			}
+			return time.Time{}, t.conversionError("time.Time", ErrOverflow)
+		}
+		if unit%time.Second == 0 {
+			if mul := int64(unit / time.Second); n <= maxUnixSec/mul && n >= -maxUnixSec/mul {
+				return time.Unix(n*mul, 0), nil
+			}
+			return time.Time{}, t.conversionError("time.Time", ErrOverflow)
+		}
+	}
+	// a fraction, or an unusual unit
+	r, ok := parseRat(s)
+	if !ok {
+		return time.Time{}, t.conversionError("time.Time", ErrOverflow)
+	}
+	r.Mul(r, new(big.Rat).SetInt64(int64(unit)))
+	ns := new(big.Int).Quo(r.Num(), r.Denom())
+	if r.Sign() < 0 && !r.IsInt() {
+		// floor rather than truncate
+		ns.Sub(ns, big.NewInt(1))
+	}
+	sec, nsec := new(big.Int).DivMod(ns, big.NewInt(int64(time.Second)), new(big.Int))
+	if !sec.IsInt64() || sec.Int64() > maxUnixSec || sec.Int64() < -maxUnixSec {
+		return time.Time{}, t.conversionError("time.Time", ErrOverflow)
+	}
+	return time.Unix(sec.Int64(), nsec.Int64()), nil
+}
+
+// maxUnixSec limits the seconds of the Unix times, which is about the
+// year 292277026596, beyond which time.Time overflows.
+const maxUnixSec = 1<<63 - 1 - (1969*365+1969/4-1969/100+1969/400)*86400
+
+// compareTimes compares the strings a and b chronologically if both are
+// RFC3339 times, and returns false otherwise.
+func compareTimes(a, b string) (int, bool) {
+	if !isTimeLike(a) || !isTimeLike(b) {
+		return 0, false
+	}
+	ta, err := time.Parse(time.RFC3339, a)
+	if err != nil {
+		return 0, false
+	}
+	tb, err := time.Parse(time.RFC3339, b)
+	if err != nil {
+		return 0, false
+	}
+	switch {
+	case ta.Before(tb):
+		return -1, true
+	case ta.After(tb):
+		return 1, true
+	}
+	return 0, true
+}
+
+// isTimeLike tells cheaply if s may be a RFC3339 time, like
+// 2006-01-02T15:04:05Z.
+func isTimeLike(s string) bool {
+	return len(s) >= 20 && s[4] == '-' && s[7] == '-' && (s[10] == 'T' || s[10] == 't') && s[13] == ':'
+}
@@ -0,0 +1,89 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimeConversions(t *testing.T) {
+	json := `{"rfc3339":"2024-03-01T12:30:45.5+02:00","rfc1123":"Fri, 01 Mar 2024 10:30:45 GMT",
+		"date":"20240301","num":20240301,"sec":1709289045,"ms":1709289045500,"frac":1709289045.25,
+		"neg":-1.5,"str":"1709289045","huge":1e30,"word":"abc","flag":true}`
+	want := time.Date(2024, 3, 1, 10, 30, 45, 0, time.UTC)
+
+	tm, err := Get(json, "rfc3339").TimeE()
+	assert(t, err == nil && tm.Equal(want.Add(500*time.Millisecond)))
+	assert(t, Get(json, "rfc1123").TimeLayout(time.RFC1123).Equal(want))
+	assert(t, Get(json, "date").TimeLayout("20060102").Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+	assert(t, Get(json, "num").TimeLayout("20060102").Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+
+	assert(t, Get(json, "sec").TimeUnix(time.Second).Equal(want))
+	assert(t, Get(json, "str").TimeUnix(time.Second).Equal(want))
+	assert(t, Get(json, "ms").TimeUnix(time.Millisecond).Equal(want.Add(500*time.Millisecond)))
+	assert(t, Get(json, "frac").TimeUnix(time.Second).Equal(want.Add(250*time.Millisecond)))
+	assert(t, Get(json, "neg").TimeUnix(time.Second).Equal(time.Unix(-2, 5e8)))
+	assert(t, Get(json, "sec").TimeUnix(time.Minute).Equal(time.Unix(1709289045*60, 0)))
+	assert(t, Get(json, "sec").TimeUnix(time.Second/3).Equal(time.Unix(0, 1709289045*int64(time.Second/3))))
+
+	_, err = Get(json, "huge").TimeUnixE(time.Second)
+	assert(t, errors.Is(err, ErrOverflow))
+	_, err = Get(`9223372036854775807`, "@this").TimeUnixE(time.Second)
+	assert(t, errors.Is(err, ErrOverflow))
+	_, err = Get(`9223372036854775807`, "@this").TimeUnixE(time.Hour)
+	assert(t, errors.Is(err, ErrOverflow))
+	_, err = Get(json, "word").TimeUnixE(time.Second)
+	assert(t, errors.Is(err, ErrType))
+	_, err = Get(json, "flag").TimeE()
+	assert(t, errors.Is(err, ErrType))
+	_, err = Get(json, "missing").TimeE()
+	assert(t, errors.Is(err, ErrNotFound))
+
+	var perr *time.ParseError
+	var cerr *ConversionError
+	tm, err = Get(json, "rfc1123").TimeE()
+	assert(t, errors.As(err, &perr) && errors.As(err, &cerr) && cerr.Type == "time.Time" && tm.IsZero())
+	assert(t, Get(json, "word").TimeLayout(time.RFC1123).IsZero())
+	assert(t, Get(json, "word").TimeUnix(time.Second).IsZero())
+}
+
+func TestTimeQueries(t *testing.T) {
+	json := `{"events":[{"id":1,"ts":"2023-12-31T23:30:00-01:00"},{"id":2,"ts":"2023-12-31T23:00:00Z"},
+		{"id":3,"ts":"2024-01-01T00:00:00.5Z"},{"id":4,"ts":"2024-01-01T08:00:00+09:00"},{"id":5,"ts":"soon"}]}`
+
+	// by default, the times are compared as strings
+	assert(t, Get(json, `events.#(ts>"2024-01-01T00:00:00Z")#.id`).Raw == "[4,5]")
+	assert(t, Get(json, `events.#(ts<"2024-01-01T00:00:00Z")#.id`).Raw == "[1,2,3]")
+	assert(t, Get(json, `events.#(ts=="2023-12-31T23:00:00Z")#.id`).Raw == "[2]")
+
+	// chronologically, 2023-12-31T23:30:00-01:00 is after 2024-01-01, while
+	// "soon" is not a time and is compared as a string
+	chrono := NewParser(Options{ChronologicalTimes: true})
+	assert(t, chrono.Get(json, `events.#(ts>"2024-01-01T00:00:00Z")#.id`).Raw == "[1,3,5]")
+	assert(t, chrono.Get(json, `events.#(ts<"2024-01-01T00:00:00Z")#.id`).Raw == "[2,4]")
+	assert(t, chrono.Get(json, `events.#(ts>="2024-01-01T00:30:00+00:30")#.id`).Raw == "[1,3,5]")
+	assert(t, chrono.Get(json, `events.#(ts<="2024-01-01T00:30:00+00:30")#.id`).Raw == "[2,4]")
+	// equality still compares strings
+	assert(t, chrono.Get(json, `events.#(ts=="2023-12-31T23:00:00Z")#.id`).Raw == "[2]")
+	assert(t, chrono.Get(json, `events.#(ts!="2023-12-31T23:00:00Z")#.id`).Raw == "[1,3,4,5]")
+	assert(t, chrono.Get(`[{"ts":"2024-01-01T00:00:00Z"},{"ts":"2024-01-01T01:00:00+01:00"}]`,
+		`#(ts=="2024-01-01T00:00:00Z")#.ts`).Raw == `["2024-01-01T00:00:00Z"]`)
+	// patterns are not times
+	assert(t, chrono.Get(json, `events.#(ts%"2024-01-01T*")#.id`).Raw == "[3,4]")
+}
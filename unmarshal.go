@@ -0,0 +1,57 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"github.com/cloudwego/gjson/internal/fast"
+)
+
+// Unmarshal decodes the raw json of the result into v, like json.Unmarshal
+// does, honouring the json struct tags. The json is decoded by the native
+// decoder, which is compatible with encoding/json, and the errors are the
+// ones of the decoder. ErrNotFound is returned if the result does not exist.
+//
+//	var friends []struct {
+//		First string `json:"first"`
+//		Age   int    `json:"age"`
+//	}
+//	err := gjson.Get(json, "friends").Unmarshal(&friends)
+func (t Result) Unmarshal(v interface{}) error {
+	if !t.Exists() {
+		return ErrNotFound
+	}
+	raw := t.Raw
+	if raw == "" {
+		// calculated result
+		raw = t.String()
+	}
+	return fast.Unmarshal(raw, v)
+}
+
+// GetInto searches json for the specified path, and decodes the value into v,
+// see Result.Unmarshal. The errors of searching are the ones of GetE.
+func GetInto(json, path string, v interface{}) error {
+	return getInto(json, path, v, nil)
+}
+
+func getInto(json, path string, v interface{}, opts *Options) error {
+	res, err := getE(json, path, opts)
+	if err != nil {
+		return err
+	}
+	return res.Unmarshal(v)
+}
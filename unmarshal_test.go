@@ -0,0 +1,60 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnmarshal(t *testing.T) {
+	type friend struct {
+		First string   `json:"first"`
+		Last  string   `json:"last"`
+		Age   int      `json:"age"`
+		Nets  []string `json:"nets,omitempty"`
+		Other string   `json:"-"`
+	}
+	var friends []friend
+	err := Get(readmeJSON, "friends").Unmarshal(&friends)
+	assert(t, err == nil && len(friends) == 3)
+	assert(t, friends[0].First == "Dale" && friends[0].Age == 44 && len(friends[0].Nets) == 3)
+
+	var f friend
+	err = GetInto(readmeJSON, `friends.#(last=="Murphy")`, &f)
+	assert(t, err == nil && f.Last == "Murphy" && f.Nets[1] == "fb")
+	var age int
+	err = GetInto(readmeJSON, "friends.#(first==Roger).age", &age)
+	assert(t, err == nil && age == 68)
+	var names []string
+	err = GetInto(readmeJSON, "friends.#.first", &names)
+	assert(t, err == nil && len(names) == 3 && names[2] == "Jane")
+	var n int
+	err = Get(readmeJSON, "friends.#").Unmarshal(&n)
+	assert(t, err == nil && n == 3)
+	var m map[string]interface{}
+	err = NewParser(Options{}).GetInto(readmeJSON, "name", &m)
+	assert(t, err == nil && m["first"] == "Tom")
+
+	err = GetInto(readmeJSON, "name.middle", &m)
+	assert(t, err == ErrNotFound)
+	var perr *PathSyntaxError
+	err = GetInto(readmeJSON, "friends.#(age>40", &m)
+	assert(t, errors.As(err, &perr))
+	err = GetInto(readmeJSON, "name.first", &age)
+	assert(t, err != nil)
+}
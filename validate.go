@@ -0,0 +1,221 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"strconv"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// SyntaxError describes where and why a json is invalid.
+type SyntaxError struct {
+	// Offset is the position in bytes where the error was found
+	Offset int
+	// Line and Column are the 1-based line and column, in bytes, of Offset
+	Line, Column int
+	// Msg is the reason of the error, such as "unterminated string"
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return "gjson: " + e.Msg + " at line " + strconv.Itoa(e.Line) +
+		", column " + strconv.Itoa(e.Column) + " (offset " + strconv.Itoa(e.Offset) + ")"
+}
+
+// Validate returns nil if the input is valid json, otherwise a *SyntaxError
+// telling where and why it is invalid.
+//
+//	if err := gjson.Validate(json); err != nil {
+//		return err
+//	}
+//	value := gjson.Get(json, "name.last")
+func Validate(json string) error {
+	return validate(stringBytes(json), false)
+}
+
+// ValidateBytes returns nil if the input is valid json, otherwise a
+// *SyntaxError telling where and why it is invalid.
+//
+// If working with bytes, this method preferred over Validate(string(data))
+func ValidateBytes(json []byte) error {
+	return validate(json, false)
+}
+
+// ValidStrict returns true if the input is valid json, and all its strings
+// are valid UTF-8 without lone surrogates in the \u escapes, which are
+// accepted by Valid but replaced by encoding/json.
+func ValidStrict(json string) bool {
+	return Valid(json) && invalidUTF8(stringBytes(json)) < 0
+}
+
+// ValidStrictBytes returns true if the input is valid json, and all its
+// strings are valid UTF-8, see ValidStrict.
+//
+// If working with bytes, this method preferred over ValidStrict(string(data))
+func ValidStrictBytes(json []byte) bool {
+	return ValidBytes(json) && invalidUTF8(json) < 0
+}
+
+func validate(data []byte, strict bool) error {
+	if i, ok := validjson(data); !ok {
+		return syntaxError(data, i)
+	}
+	if strict {
+		if i := invalidUTF8(data); i >= 0 {
+			return newSyntaxError(data, i, "invalid UTF-8 in string")
+		}
+	}
+	return nil
+}
+
+// invalidUTF8 returns the offset of the first invalid UTF-8 sequence, or lone
+// surrogate escape, in a valid json, or -1 if there is none.
+// Bytes out of strings are all ASCII in a valid json, thus it checks the whole
+// json, and a backslash always starts an escape.
+func invalidUTF8(data []byte) int {
+	for i := 0; i < len(data); {
+		c := data[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRune(data[i:])
+			if r == utf8.RuneError && size == 1 {
+				return i
+			}
+			i += size
+			continue
+		}
+		if c != '\\' {
+			i++
+			continue
+		}
+		if i+6 > len(data) || data[i+1] != 'u' {
+			i += 2
+			continue
+		}
+		r := runeit(bytesString(data[i+2:]))
+		if utf16.IsSurrogate(r) {
+			if r >= 0xDC00 || i+12 > len(data) || data[i+6] != '\\' || data[i+7] != 'u' ||
+				utf16.DecodeRune(r, runeit(bytesString(data[i+8:]))) == unicode.ReplacementChar {
+				return i
+			}
+			i += 6
+		}
+		i += 6
+	}
+	return -1
+}
+
+// syntaxError tells the reason why validpayload stopped at the offset i of data.
+// The input before i is well-formed, so it is only tokenized to find the token
+// containing i.
+func syntaxError(data []byte, i int) *SyntaxError {
+	if i > len(data) {
+		i = len(data)
+	}
+	msg := ""
+	depth, done := 0, false
+	for j := 0; j < i && j < len(data) && msg == ""; {
+		start := j
+		switch data[j] {
+		case ' ', '\t', '\n', '\r', ',', ':':
+			j++
+			continue
+		case '{', '[':
+			depth++
+			j++
+			continue
+		case '}', ']':
+			depth--
+			j++
+		case '"':
+			ok := false
+			for j = start + 1; j < len(data); j++ {
+				if data[j] == '\\' {
+					j++
+				} else if data[j] == '"' {
+					ok = true
+					break
+				}
+			}
+			if i <= j || !ok {
+				switch {
+				case i >= len(data):
+					msg = "unterminated string"
+				case data[i] < ' ':
+					msg = "invalid character " + quoteChar(data[i:]) + " in string"
+				default:
+					msg = "invalid escape in string"
+				}
+				continue
+			}
+			j++
+		case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			for j++; j < len(data); j++ {
+				if (data[j] < '0' || data[j] > '9') && data[j] != '.' &&
+					data[j] != 'e' && data[j] != 'E' && data[j] != '+' && data[j] != '-' {
+					break
+				}
+			}
+			if end, ok := validnumber(data[:j], start+1); i <= j && (!ok || end != j) {
+				msg = "invalid number"
+				continue
+			}
+		default:
+			for j++; j < len(data); j++ {
+				if data[j] < 'a' || data[j] > 'z' {
+					break
+				}
+			}
+			if s := string(data[start:j]); s != "true" && s != "false" && s != "null" {
+				i, msg = start, "invalid literal "+strconv.Quote(s)
+				continue
+			}
+		}
+		if depth == 0 {
+			done = true
+		}
+	}
+	if msg == "" {
+		switch {
+		case i >= len(data):
+			msg = "unexpected end of input"
+		case done && depth == 0:
+			msg = "unexpected data after top-level value"
+		default:
+			msg = "unexpected character " + quoteChar(data[i:])
+		}
+	}
+	return newSyntaxError(data, i, msg)
+}
+
+func newSyntaxError(data []byte, offset int, msg string) *SyntaxError {
+	line, col := 1, 1
+	for _, c := range data[:offset] {
+		if c == '\n' {
+			line, col = line+1, 1
+		} else {
+			col++
+		}
+	}
+	return &SyntaxError{Offset: offset, Line: line, Column: col, Msg: msg}
+}
+
+func quoteChar(data []byte) string {
+	r, _ := utf8.DecodeRune(data)
+	return strconv.QuoteRune(r)
+}
@@ -0,0 +1,183 @@
+/**
+ * Copyright 2024 Cloudwego Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gjson
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		json   string
+		offset int
+		line   int
+		column int
+		msg    string
+	}{
+		{"", 0, 1, 1, "unexpected end of input"},
+		{`{"a":1,}`, 7, 1, 8, "unexpected character '}'"},
+		{`{"a" 1}`, 5, 1, 6, "unexpected character '1'"},
+		{`[1,2`, 4, 1, 5, "unexpected end of input"},
+		{`{"a":"b`, 7, 1, 8, "unterminated string"},
+		{"\"a\x01\"", 2, 1, 3, `invalid character '\x01' in string`},
+		{`"a\x"`, 3, 1, 4, "invalid escape in string"},
+		{`"\u12g4"`, 5, 1, 6, "invalid escape in string"},
+		{`[-]`, 2, 1, 3, "invalid number"},
+		{`[1.]`, 3, 1, 4, "invalid number"},
+		{`01`, 1, 1, 2, "invalid number"},
+		{`[nul]`, 1, 1, 2, `invalid literal "nul"`},
+		{`{} {}`, 3, 1, 4, "unexpected data after top-level value"},
+		{"{\n  \"a\": [1, 2,, 3]\n}", 15, 2, 14, "unexpected character ','"},
+		{"[\xff]", 1, 1, 2, "unexpected character '�'"},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.json)
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			t.Errorf("Validate(%q) = %v, want a *SyntaxError", tt.json, err)
+			continue
+		}
+		if se.Offset != tt.offset || se.Line != tt.line || se.Column != tt.column || se.Msg != tt.msg {
+			t.Errorf("Validate(%q) = %+v, want %d %d:%d %q", tt.json, *se, tt.offset, tt.line, tt.column, tt.msg)
+		}
+		if ValidateBytes([]byte(tt.json)) == nil || Valid(tt.json) {
+			t.Errorf("ValidateBytes(%q) = nil", tt.json)
+		}
+	}
+	for _, json := range []string{exampleJSON, basicJSON, complicatedJSON, `1`, ` "a" `, `[true,false,null]`} {
+		if err := Validate(json); err != nil {
+			t.Errorf("Validate(%q) = %v", json, err)
+		}
+		for i := range json {
+			if err := Validate(json[:i]); (err == nil) != Valid(json[:i]) {
+				t.Errorf("Validate(%q) = %v", json[:i], err)
+			}
+		}
+	}
+}
+
+func TestValidateLarge(t *testing.T) {
+	large := "[" + strings.Repeat(`{"a":[1,2.5e3,"é"],"b":null},`, fastValidThreshold/32) + "{}]"
+	if err := Validate(large); err != nil {
+		t.Fatal(err)
+	}
+	broken := large[:len(large)-3] + `{"c":tru}]`
+	err := Validate(broken)
+	se, ok := err.(*SyntaxError)
+	if !ok || se.Offset != len(large)-3+5 || se.Msg != `invalid literal "tru"` {
+		t.Fatalf("Validate() = %v", err)
+	}
+	// the strings of large inputs are checked too
+	escape := large[:len(large)-3] + `"a\x"]`
+	if err := Validate(escape); err == nil {
+		t.Fatal("expected an error for an invalid escape")
+	}
+	control := large[:len(large)-3] + "\"a\x01\"]"
+	if err := Validate(control); err == nil {
+		t.Fatal("expected an error for a control character")
+	}
+}
+
+func TestValidStrict(t *testing.T) {
+	tests := []struct {
+		json   string
+		offset int // -1 if valid
+	}{
+		{`"abc☺"`, -1},
+		{`"😀"`, -1},
+		{`"\\ud800"`, -1},
+		{`"é￿"`, -1},
+		{"\"a\xffb\"", 2},
+		{"\"\xed\xa0\x80\"", 1},
+		{"\"\xe2\x98\"", 1},
+		{`"\ud800"`, 1},
+		{`"\ud800x"`, 1},
+		{`"\ude00\ud83d"`, 1},
+		{`"\ud83dA"`, 1},
+		{`"a😀\ud83d"`, 6},
+	}
+	strict := NewParser(Options{ValidateUTF8: true})
+	for _, tt := range tests {
+		// the same results below and above the threshold of the native validator
+		for _, json := range []string{tt.json, tt.json + strings.Repeat(" ", fastValidThreshold)} {
+			if !Valid(json) {
+				t.Fatalf("Valid(%q) = false", tt.json)
+			}
+			if ValidStrict(json) != (tt.offset < 0) || ValidStrictBytes([]byte(json)) != (tt.offset < 0) ||
+				strict.Valid(json) != (tt.offset < 0) || strict.ValidBytes([]byte(json)) != (tt.offset < 0) {
+				t.Errorf("ValidStrict(%q) = %v", tt.json, !(tt.offset < 0))
+			}
+			err := strict.Validate(json)
+			if se, _ := err.(*SyntaxError); (tt.offset < 0) != (err == nil) || se != nil && se.Offset != tt.offset {
+				t.Errorf("Validate(%q) = %v, want offset %d", tt.json, err, tt.offset)
+			}
+			if NewParser(Options{}).Validate(json) != nil {
+				t.Errorf("Validate(%q) != nil without ValidateUTF8", tt.json)
+			}
+		}
+	}
+	assert(t, !ValidStrict(`"abc`) && strict.Validate(`"abc`) != nil)
+}
+
+// TestValidConformance checks that the results of the validators never depend
+// on the size of json, which are the Go ones below fastValidThreshold and the
+// native ones above it. The cases are named like the JSONTestSuite ones, which
+// are y_ for valid json, n_ for invalid json, and i_ for the implementation
+// defined ones, all with valid syntax but not strictly valid.
+func TestValidConformance(t *testing.T) {
+	files, err := filepath.Glob("testdata/conformance/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatal("no conformance cases", err)
+	}
+	cases := map[string]string{
+		"y_structure_deep_nesting":  strings.Repeat("[", 6000) + strings.Repeat("]", 6000),
+		"n_structure_deep_unclosed": strings.Repeat("[", 6000) + strings.Repeat("]", 5999),
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cases[strings.TrimSuffix(filepath.Base(file), ".json")] = string(data)
+	}
+	for name, json := range cases {
+		large := json + "\n" + strings.Repeat(" ", fastValidThreshold)
+		valid, strict := Valid(json), ValidStrict(json)
+		switch name[0] {
+		case 'y':
+			assert(t, valid && strict)
+		case 'n':
+			assert(t, !valid && !strict)
+		case 'i':
+			assert(t, valid)
+		}
+		_, ok := validpayload([]byte(large), 0)
+		if Valid(large) != valid || ValidBytes([]byte(large)) != valid || ok != valid {
+			t.Errorf("%s: Valid() = %v, but %v for a large json", name, valid, !valid)
+		}
+		if ValidStrict(large) != strict {
+			t.Errorf("%s: ValidStrict() = %v, but %v for a large json", name, strict, !strict)
+		}
+		if (Validate(json) == nil) != valid || (Validate(large) == nil) != valid {
+			t.Errorf("%s: Validate() = %v, but Valid() = %v", name, Validate(large), valid)
+		}
+	}
+}